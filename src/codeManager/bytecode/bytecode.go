@@ -0,0 +1,324 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package bytecode stores analysed code in a versioned binary file, so it can run without analysing again.
+//
+// A file is laid out as below, integers are little endian and the body uses varints:
+//
+//	magic "BFCK", version uint16, flags byte (bit 0: debug), optimisation level byte
+//	SHA-256 of the source text, 32 bytes
+//	body: operator count, line count, each operator (operator byte, auxiliary, source line, source column, offset),
+//	      multiply targets, and line begins in debug mode
+//	CRC-32 (IEEE) of everything above, uint32
+package bytecode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+)
+
+// Version of the file format, files of other versions are rejected.
+const Version = 2
+
+// Extension is the file extension of bytecode files.
+const Extension = ".bfc"
+
+const (
+	magic      = "BFCK"
+	headerSize = 8 + sha256.Size
+	flagDebug  = 1
+)
+
+var ErrInvalid = errors.New("invalid bytecode")
+
+// File is the content of a bytecode file.
+//
+// Code has LineBegins only if it was analysed in debug mode.
+type File struct {
+	Code       *code.Code
+	Level      codeanalyser.OptLevel
+	SourceHash [sha256.Size]byte // SHA-256 of the source text
+}
+
+// Write writes file in bytecode format to output.
+func Write(output io.Writer, file *File) (err error) {
+	var c *code.Code = file.Code
+	var buffer *bytes.Buffer = new(bytes.Buffer)
+
+	// Header
+	buffer.WriteString(magic)
+	buffer.Write(binary.LittleEndian.AppendUint16(nil, Version))
+	var flags byte = 0
+	if c.LineBegins != nil {
+		flags |= flagDebug
+	}
+	buffer.WriteByte(flags)
+	buffer.WriteByte(byte(file.Level))
+	buffer.Write(file.SourceHash[:])
+
+	// Operators
+	buffer.Write(binary.AppendUvarint(nil, uint64(c.CodeCount)))
+	buffer.Write(binary.AppendUvarint(nil, c.LineCount))
+	for index := range c.CodeCount {
+		buffer.WriteByte(byte(c.Operators[index]))
+		buffer.Write(binary.AppendUvarint(nil, c.Auxiliary[index]))
+		buffer.Write(binary.AppendUvarint(nil, uint64(c.SourceLines[index])))
+		buffer.Write(binary.AppendUvarint(nil, uint64(c.SourceColumns[index])))
+		buffer.Write(binary.AppendVarint(nil, int64(c.Offsets[index])))
+	}
+
+	// Multiply targets
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(c.MulTargets))))
+	for _, targets := range c.MulTargets {
+		buffer.Write(binary.AppendUvarint(nil, uint64(len(targets))))
+		for _, target := range targets {
+			buffer.Write(binary.AppendVarint(nil, int64(target.Offset)))
+			buffer.Write(binary.AppendVarint(nil, target.Factor))
+		}
+	}
+
+	// Line begins
+	if c.LineBegins != nil {
+		for _, begin := range c.LineBegins {
+			buffer.Write(binary.AppendVarint(nil, int64(begin)))
+		}
+	}
+
+	buffer.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(buffer.Bytes())))
+	_, err = output.Write(buffer.Bytes())
+	return
+}
+
+// Read reads a file in bytecode format from input, checking its version, checksum and structure.
+func Read(input io.Reader) (ret *File, err error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return
+	}
+
+	// Check header and checksum
+	if len(data) < headerSize+4 || string(data[:4]) != magic {
+		return nil, errors.New("Error: not a bytecode file")
+	}
+	var version uint16 = binary.LittleEndian.Uint16(data[4:])
+	if version != Version {
+		return nil, fmt.Errorf("Error: unsupported bytecode version %v, expect %v", version, Version)
+	}
+	var body []byte = data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, errors.New("Error: bytecode checksum mismatch, file is corrupted")
+	}
+
+	var flags byte = data[6]
+	ret = &File{Level: codeanalyser.OptLevel(data[7])}
+	copy(ret.SourceHash[:], data[8:headerSize])
+	if ret.Level > codeanalyser.O2 || flags&^flagDebug != 0 {
+		return nil, fmt.Errorf("Error: %w: unknown level or flags", ErrInvalid)
+	}
+
+	// Body
+	var r *reader = &reader{data: body[headerSize:]}
+	ret.Code = r.code(flags&flagDebug != 0)
+	if r.err == nil && len(r.data) != 0 {
+		r.fail("unexpected data after code")
+	}
+	if r.err == nil {
+		r.err = validate(ret.Code)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("Error: %w: %v", ErrInvalid, r.err)
+	}
+	return
+}
+
+// reader decodes varints from data, the first error is kept and later reads return 0.
+type reader struct {
+	data []byte
+	err  error
+}
+
+// code decodes code written by Write.
+func (r *reader) code(debugFlag bool) (ret *code.Code) {
+	ret = code.New(debugFlag)
+	ret.CodeCount = r.count()
+	ret.LineCount = r.unsigned()
+
+	// Operators
+	ret.Operators = make([]code.Operator, ret.CodeCount)
+	ret.Auxiliary = make([]uint64, ret.CodeCount)
+	ret.SourceLines = make([]int, ret.CodeCount)
+	ret.SourceColumns = make([]int, ret.CodeCount)
+	ret.Offsets = make([]int, ret.CodeCount)
+	for index := range ret.CodeCount {
+		ret.Operators[index] = code.Operator(r.byte())
+		ret.Auxiliary[index] = r.unsigned()
+		ret.SourceLines[index] = int(r.unsigned())
+		ret.SourceColumns[index] = int(r.unsigned())
+		ret.Offsets[index] = int(r.signed())
+	}
+
+	// Multiply targets
+	ret.MulTargets = make([][]code.MulTarget, r.count())
+	for index := range ret.MulTargets {
+		ret.MulTargets[index] = make([]code.MulTarget, r.count())
+		for targetIndex := range ret.MulTargets[index] {
+			ret.MulTargets[index][targetIndex].Offset = int(r.signed())
+			ret.MulTargets[index][targetIndex].Factor = r.signed()
+		}
+	}
+
+	// Line begins
+	if debugFlag {
+		if ret.LineCount > uint64(len(r.data)) {
+			r.fail("too many lines")
+			return
+		}
+		ret.LineBegins = make([]int, ret.LineCount)
+		for index := range ret.LineBegins {
+			ret.LineBegins[index] = int(r.signed())
+		}
+	}
+	return
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil || len(r.data) == 0 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+	var ret byte = r.data[0]
+	r.data = r.data[1:]
+	return ret
+}
+
+func (r *reader) unsigned() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, length := binary.Uvarint(r.data)
+	if length <= 0 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+	r.data = r.data[length:]
+	return value
+}
+
+func (r *reader) signed() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, length := binary.Varint(r.data)
+	if length <= 0 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+	r.data = r.data[length:]
+	return value
+}
+
+// count reads a length, which can't be more than bytes left since every element takes at least one byte.
+func (r *reader) count() int {
+	var value uint64 = r.unsigned()
+	if value > uint64(len(r.data)) {
+		r.fail("length beyond end of data")
+		return 0
+	}
+	return int(value)
+}
+
+func (r *reader) fail(message string) {
+	if r.err == nil {
+		r.err = errors.New(message)
+	}
+}
+
+// validate checks that code can be run without panic, like code produced by the analyser.
+func validate(c *code.Code) error {
+	if c.CodeCount == 0 {
+		return errors.New("no operators")
+	}
+
+	var stack []int = make([]int, 0)
+	for index, operator := range c.Operators {
+		var auxiliary uint64 = c.Auxiliary[index]
+		if c.SourceLines[index] < 1 || uint64(c.SourceLines[index]) > c.LineCount {
+			return fmt.Errorf("source line out of range at operator %v", index)
+		}
+		if c.SourceColumns[index] < 1 {
+			return fmt.Errorf("source column out of range at operator %v", index)
+		}
+
+		switch operator {
+		case code.OpAdd, code.OpSub, code.OpMoveLeft, code.OpMoveRight, code.OpInput, code.OpOutput, code.OpClear, code.OpSet:
+			// Any auxiliary data
+
+		case code.OpLeftBracket:
+			stack = append(stack, index)
+
+		case code.OpRightBracket:
+			// Both brackets hold the index after their matching one
+			if len(stack) == 0 {
+				return fmt.Errorf("unmatched right bracket at operator %v", index)
+			}
+			var left int = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if auxiliary != uint64(left+1) || c.Auxiliary[left] != uint64(index+1) {
+				return fmt.Errorf("wrong jump of loop at operator %v", left)
+			}
+
+		case code.OpScan:
+			if auxiliary == 0 {
+				return fmt.Errorf("zero stride at operator %v", index)
+			}
+
+		case code.OpMultiply:
+			// A loop follows, and targets are sorted by offset
+			if index+1 >= c.CodeCount || c.Operators[index+1] != code.OpLeftBracket {
+				return fmt.Errorf("multiply without loop at operator %v", index)
+			}
+			if auxiliary >= uint64(len(c.MulTargets)) || len(c.MulTargets[auxiliary]) == 0 {
+				return fmt.Errorf("wrong multiply targets at operator %v", index)
+			}
+			if !slices.IsSortedFunc(c.MulTargets[auxiliary], func(a, b code.MulTarget) int { return a.Offset - b.Offset }) {
+				return fmt.Errorf("unsorted multiply targets at operator %v", index)
+			}
+
+		default:
+			return fmt.Errorf("unknown operator %v at index %v", operator, index)
+		}
+	}
+	if len(stack) != 0 {
+		return fmt.Errorf("unmatched left bracket at operator %v", stack[len(stack)-1])
+	}
+
+	for line, begin := range c.LineBegins {
+		if begin < -1 || begin >= c.CodeCount {
+			return fmt.Errorf("line begin out of range at line %v", line+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bytecode_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/bytecode"
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+)
+
+// headerSize is the size of magic, version, flags, level and source hash.
+const headerSize = 8 + sha256.Size
+
+// source covers multiply, scan, offsets, an empty line and a line without operators at the end.
+const source = "+++[>++<-]>>+<[>]<\n\n,[.,]\nend"
+
+// analyse returns source analysed at -O2 as a bytecode file.
+func analyse(t *testing.T, debugFlag bool) (ret *bytecode.File) {
+	t.Helper()
+	c, err := codeanalyser.Analyse(source, debugFlag, codeanalyser.O2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &bytecode.File{Code: c, Level: codeanalyser.O2, SourceHash: sha256.Sum256([]byte(source))}
+}
+
+// write returns file in bytecode format.
+func write(t *testing.T, file *bytecode.File) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := bytecode.Write(&buffer, file); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// resign replaces the checksum at the end of data, so changes reach the checks behind it.
+func resign(data []byte) []byte {
+	var body []byte = data[:len(data)-4]
+	return binary.LittleEndian.AppendUint32(body, crc32.ChecksumIEEE(body))
+}
+
+// flip returns a copy of data with the byte at index inverted.
+func flip(data []byte, index int) (ret []byte) {
+	ret = slices.Clone(data)
+	ret[index] ^= 0xFF
+	return
+}
+
+// bump returns a copy of data with the next version, checksum updated.
+func bump(data []byte) (ret []byte) {
+	ret = slices.Clone(data)
+	binary.LittleEndian.PutUint16(ret[4:], bytecode.Version+1)
+	return resign(ret)
+}
+
+// truncate returns data with its body cut to length bytes after the header, checksum updated.
+func truncate(data []byte, length int) []byte {
+	var ret []byte = slices.Clone(data[:headerSize+length])
+	return resign(append(ret, 0, 0, 0, 0))
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, debugFlag := range []bool{false, true} {
+		var file *bytecode.File = analyse(t, debugFlag)
+		got, err := bytecode.Read(bytes.NewReader(write(t, file)))
+		if err != nil {
+			t.Fatalf("debug %v: %v", debugFlag, err)
+		}
+		if !reflect.DeepEqual(got, file) {
+			t.Errorf("debug %v: read %+v, expect %+v", debugFlag, got.Code, file.Code)
+		}
+		if (got.Code.LineBegins != nil) != debugFlag {
+			t.Errorf("debug %v: line begins are %v", debugFlag, got.Code.LineBegins)
+		}
+	}
+}
+
+// TestRejected checks that damaged files are rejected with the reason, never read as code.
+func TestRejected(t *testing.T) {
+	var data []byte = write(t, analyse(t, true))
+
+	// Code written as is, Write doesn't validate
+	var brokenJump *bytecode.File = analyse(t, false)
+	brokenJump.Code.Auxiliary[slices.Index(brokenJump.Code.Operators, code.OpLeftBracket)]++
+	var noLoop *bytecode.File = analyse(t, false)
+	noLoop.Code.Operators[slices.Index(noLoop.Code.Operators, code.OpMultiply)+1] = code.OpAdd
+
+	var cases = []struct {
+		name    string
+		data    []byte
+		invalid bool   // Whether the error wraps ErrInvalid
+		message string // Part of the error message
+	}{
+		{"flipped byte", flip(data, len(data)/2), false, "checksum mismatch"},
+		{"flipped checksum", flip(data, len(data)-1), false, "checksum mismatch"},
+		{"bumped version", bump(data), false, "unsupported bytecode version"},
+		{"truncated count", truncate(data, 12), true, "length beyond end of data"},
+		{"truncated operators", truncate(data, (len(data)-4-headerSize)/2), true, "unexpected end of data"},
+		{"truncated line begins", truncate(data, len(data)-4-headerSize-2), true, "too many lines"},
+		{"truncated header", data[:20], false, "not a bytecode file"},
+		{"broken jump", write(t, brokenJump), true, "wrong jump of loop"},
+		{"multiply without loop", write(t, noLoop), true, "multiply without loop"},
+	}
+	for _, each := range cases {
+		file, err := bytecode.Read(bytes.NewReader(each.data))
+		if err == nil {
+			t.Errorf("%v: read %+v, expect error", each.name, file.Code)
+			continue
+		}
+		if errors.Is(err, bytecode.ErrInvalid) != each.invalid || !strings.Contains(err.Error(), each.message) {
+			t.Errorf("%v: error %q, expect %q", each.name, err, each.message)
+		}
+	}
+}
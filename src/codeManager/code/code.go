@@ -21,18 +21,29 @@ import "fmt"
 
 type Operator byte
 
+// Auxiliary data of each operator is noted after it.
 const (
-	OpAdd = iota
-	OpSub
-	OpMoveLeft
-	OpMoveRight
-	OpLeftBracket
-	OpRightBracket
-	OpInput
-	OpOutput
-	Invalid // Only for internal use
+	OpAdd          = iota // Times
+	OpSub                 // Times
+	OpMoveLeft            // Steps
+	OpMoveRight           // Steps
+	OpLeftBracket         // Index after matching right bracket
+	OpRightBracket        // Index after matching left bracket
+	OpInput               // 1
+	OpOutput              // 1
+	OpClear               // 0 for [-], 1 for [+], set cell to zero
+	OpMultiply            // Index of MulTargets, run the following loop like [->+>++<<] in one step
+	OpScan                // Stride as int64, move pointer by stride until cell is zero, from loops like [>] or [<<]
+	OpSet                 // Value, set cell to value, from [-] followed by +
+	Invalid               // Only for internal use
 )
 
+// MulTarget is a cell changed by a multiply loop.
+type MulTarget struct {
+	Offset int   // Relative to the loop cell
+	Factor int64 // Added to target cell in each iteration
+}
+
 // code represents the analysed Brainfuck code.
 //
 // lineBegins will be nil if not in debug mode.
@@ -41,20 +52,28 @@ const (
 //
 // If no next valid position, lineBegins will store -1.
 type Code struct {
-	Operators  []Operator
-	Auxiliary  []uint64 // Auxiliary data for operators, times for +/- and moves, jump positions for brackets, 1 for i/o
-	CodeCount  int
-	LineCount  uint64 // Number of lines in the original code
-	LineBegins []int  // Begin index for each line
+	Operators     []Operator
+	Auxiliary     []uint64      // Auxiliary data for operators, see Operator constants
+	SourceLines   []int         // Source line of each operator, start from 1, used for runtime error messages
+	SourceColumns []int         // Source column of the first character of each operator, counting runes from 1
+	Offsets       []int         // Offset from pointer of the cell changed by add, sub, clear and set
+	MulTargets    [][]MulTarget // Targets of each multiply operator
+	CodeCount     int
+	LineCount     uint64 // Number of lines in the original code
+	LineBegins    []int  // Begin index for each line
 }
 
 func New(debugFlag bool) (ret *Code) {
 	ret = &Code{
-		Operators:  make([]Operator, 0),
-		Auxiliary:  make([]uint64, 0),
-		CodeCount:  0,
-		LineCount:  0,
-		LineBegins: nil,
+		Operators:     make([]Operator, 0),
+		Auxiliary:     make([]uint64, 0),
+		SourceLines:   make([]int, 0),
+		SourceColumns: make([]int, 0),
+		Offsets:       make([]int, 0),
+		MulTargets:    make([][]MulTarget, 0),
+		CodeCount:     0,
+		LineCount:     0,
+		LineBegins:    nil,
 	}
 	if debugFlag {
 		ret.LineBegins = make([]int, 0)
@@ -62,7 +81,7 @@ func New(debugFlag bool) (ret *Code) {
 	return
 }
 
-// PrintAll prints all code with auxiliary data and line begins (if any).
+// PrintAll prints all code with auxiliary data, source line and column, and line begins (if any).
 func (c *Code) PrintAll() {
 	fmt.Printf("\nTotal operators count: %v\n\n", c.CodeCount)
 
@@ -77,7 +96,7 @@ func (c *Code) PrintAll() {
 			loopCountStack = append(loopCountStack, loopCount)
 			fmt.Printf("L%v:\n", loopCount)
 		}
-		fmt.Printf("  %-8d %-15s %d\n", index, operator.String(), c.Auxiliary[index])
+		fmt.Printf("  %-8d %-15s %-24v %v:%v\n", index, operator.String(), c.FormatAuxiliary(index), c.SourceLines[index], c.SourceColumns[index])
 		// Print loop end labels
 		if operator == OpRightBracket {
 			if len(loopCountStack) == 0 {
@@ -105,7 +124,40 @@ func (c Code) Print(index int) {
 		panic("Code: code index out of range")
 	}
 
-	fmt.Printf("%-8d %-15s %d\n", index, c.Operators[index].String(), c.Auxiliary[index])
+	fmt.Printf("%-8d %-15s %v\n", index, c.Operators[index].String(), c.FormatAuxiliary(index))
+}
+
+// FormatAuxiliary returns auxiliary data of the operator at the given index as text.
+//
+// Scan stride is shown signed, multiply targets are shown after index like "0 [+1]+=1 [-2]-=3",
+// non-zero offset is shown after value like "3 [+1]".
+func (c *Code) FormatAuxiliary(index int) (ret string) {
+	switch c.Operators[index] {
+	case OpScan:
+		return fmt.Sprintf("%d", int64(c.Auxiliary[index]))
+
+	case OpAdd, OpSub, OpClear, OpSet:
+		if c.Offsets[index] != 0 {
+			return fmt.Sprintf("%d [%+d]", c.Auxiliary[index], c.Offsets[index])
+		}
+		return fmt.Sprintf("%d", c.Auxiliary[index])
+
+	case OpMultiply:
+		// Format targets below
+
+	default:
+		return fmt.Sprintf("%d", c.Auxiliary[index])
+	}
+
+	ret = fmt.Sprintf("%d", c.Auxiliary[index])
+	for _, target := range c.MulTargets[c.Auxiliary[index]] {
+		if target.Factor < 0 {
+			ret += fmt.Sprintf(" [%+d]-=%d", target.Offset, -target.Factor)
+		} else {
+			ret += fmt.Sprintf(" [%+d]+=%d", target.Offset, target.Factor)
+		}
+	}
+	return
 }
 
 // ToOperator converts a rune character to the corresponding Operator.
@@ -181,6 +233,18 @@ func (op Operator) String() string {
 
 	case OpOutput:
 		return "Output"
+
+	case OpClear:
+		return "Clear"
+
+	case OpMultiply:
+		return "Multiply"
+
+	case OpScan:
+		return "Scan"
+
+	case OpSet:
+		return "Set"
 	}
 	return "Invalid"
 }
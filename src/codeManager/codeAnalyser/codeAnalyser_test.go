@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codeanalyser_test
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/codeManager/runtimeError"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// TestLevelsSameOutput runs every program of testdata/programs at each optimisation level.
+func TestLevelsSameOutput(t *testing.T) {
+	for _, each := range testprograms.Load(t) {
+		for _, level := range []codeanalyser.OptLevel{codeanalyser.O0, codeanalyser.O1, codeanalyser.O2} {
+			c, err := codeanalyser.Analyse(each.Source, false, level)
+			if err != nil {
+				t.Fatalf("%v: %v", each.Name, err)
+			}
+			var output bytes.Buffer
+			var options coderunner.Options = coderunner.Options{Input: strings.NewReader(each.Input), Output: &output}
+			if ret := coderunner.New(c, false, options).Run(); ret != coderunner.ReturnAfterFinish {
+				t.Fatalf("%v -O%v: returned %v", each.Name, level, ret)
+			}
+			if output.String() != each.Output {
+				t.Errorf("%v -O%v: wrote %q, expect %q", each.Name, level, output.String(), each.Output)
+			}
+		}
+	}
+}
+
+// TestMergeCancels checks that -O1 merges runs and cancels opposite operators, but not across lines in debug mode.
+func TestMergeCancels(t *testing.T) {
+	var cases = []struct {
+		source    string
+		debugFlag bool
+		expect    []string
+	}{
+		{"+++.", false, []string{"Add 3", "Output"}},
+		{"++-.", false, []string{"Add 1", "Output"}},
+		{"+--.", false, []string{"Sub 1", "Output"}},
+		{"><<.", false, []string{"MoveLeft 1", "Output"}},
+		{"+<>+.", false, []string{"Add 2", "Output"}},
+		{"+\n-.", false, []string{"Output"}},
+		{"+\n-.", true, []string{"Add 1", "Sub 1", "Output"}},
+	}
+	for _, each := range cases {
+		c, err := codeanalyser.Analyse(each.source, each.debugFlag, codeanalyser.O1)
+		if err != nil {
+			t.Fatalf("%q: %v", each.source, err)
+		}
+		var got []string
+		for index, operator := range c.Operators {
+			switch operator {
+			case code.OpAdd, code.OpSub, code.OpMoveLeft, code.OpMoveRight:
+				got = append(got, fmt.Sprintf("%v %v", operator, c.Auxiliary[index]))
+
+			default:
+				got = append(got, operator.String())
+			}
+		}
+		if !slices.Equal(got, each.expect) {
+			t.Errorf("%q debug %v: analysed to %q, expect %q", each.source, each.debugFlag, got, each.expect)
+		}
+	}
+}
+
+// TestScanDetection checks which loops become scan operators at -O2.
+func TestScanDetection(t *testing.T) {
+	var cases = []struct {
+		source    string
+		debugFlag bool
+		stride    string // Empty if the loop is kept
+	}{
+		{"[>]", false, "1"},
+		{"[<]", false, "-1"},
+		{"[>>>]", false, "3"},
+		{"[<<]", false, "-2"},
+		{"[><<]", false, "-1"},
+		{"[>+]", false, ""},
+		{"[>[-]]", false, ""},
+		{"[\n>]", false, "1"},
+		{"[\n>]", true, ""},
+	}
+	for _, each := range cases {
+		c, err := codeanalyser.Analyse(each.source, each.debugFlag, codeanalyser.O2)
+		if err != nil {
+			t.Fatalf("%q: %v", each.source, err)
+		}
+		var stride string
+		if index := slices.Index(c.Operators, code.OpScan); index != -1 {
+			stride = c.FormatAuxiliary(index)
+		}
+		if stride != each.stride {
+			t.Errorf("%q debug %v: scan stride is %q, expect %q", each.source, each.debugFlag, stride, each.stride)
+		}
+	}
+}
+
+// TestLevelsSameErrorLine checks that breaking tape limits is reported on the same line at each level,
+// although -O2 moves the pointer later than the source does.
+func TestLevelsSameErrorLine(t *testing.T) {
+	var cases = []struct {
+		source string
+		config memory.Config
+		line   int
+	}{
+		{">>>\n+<<<<", memory.Config{TapeSize: 3}, 1},
+		{">>>>\n>", memory.Config{TapeSize: 3}, 1},
+		{">>>>+\n<<-", memory.Config{TapeSize: 3}, 1},
+		{"+[+]-<\n+<", memory.Config{NoNegative: true}, 1},
+		{"<\n<+>>", memory.Config{NoNegative: true}, 1},
+		{"+>\n<<-\n<", memory.Config{NoNegative: true}, 2},
+	}
+	for _, each := range cases {
+		for _, level := range []codeanalyser.OptLevel{codeanalyser.O1, codeanalyser.O2} {
+			c, err := codeanalyser.Analyse(each.source, false, level)
+			if err != nil {
+				t.Fatalf("%q: %v", each.source, err)
+			}
+			var options coderunner.Options = coderunner.Options{Input: strings.NewReader(""), Output: &bytes.Buffer{}, Memory: each.config}
+			var runner *coderunner.CodeRunner = coderunner.New(c, false, options)
+			if ret := runner.Run(); ret != coderunner.ReturnRuntimeError {
+				t.Fatalf("%q -O%v: returned %v, expect runtime error", each.source, level, ret)
+			}
+			if line := runner.RuntimeError().(*runtimeError.RuntimeError).Line(); line != each.line {
+				t.Errorf("%q -O%v: error reported on line %v, expect %v", each.source, level, line, each.line)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codeanalyser
+
+import (
+	"slices"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/ir"
+)
+
+// LoopPass replaces [-], [+] and [>] like loops with clear and scan instructions, and marks loops like
+// [->+>++<<] to run as one multiply operator.
+//
+// In debug mode, clear and scan loops across lines are kept so every line still has its own begin index.
+type LoopPass struct{}
+
+func (LoopPass) Run(program *ir.Program) {
+	program.Rewrite(func(nodes []ir.Node) (ret []ir.Node) {
+		ret = make([]ir.Node, 0, len(nodes))
+		for _, node := range nodes {
+			if loop, isLoop := node.(*ir.Loop); isLoop {
+				if instruction, replaced := loopInstruction(loop, program.DebugFlag); replaced {
+					ret = ir.AppendInstruction(ret, instruction)
+					continue
+				}
+				loop.MulTargets = multiplyTargets(loop)
+			}
+			ret = ir.AppendNode(ret, node)
+		}
+		return
+	})
+}
+
+// loopInstruction returns the clear or scan instruction replacing the loop, if the loop is [-], [+] or
+// only moves pointer like [>] or [<<].
+func loopInstruction(loop *ir.Loop, debugFlag bool) (ret ir.Instruction, replaced bool) {
+	if len(loop.Body) != 1 || (debugFlag && loop.Begin.Line != loop.End.Line) {
+		return
+	}
+	block, isBlock := loop.Body[0].(*ir.Block)
+	if !isBlock || len(block.Instructions) != 1 {
+		return
+	}
+
+	// Source line is kept from left bracket
+	var instruction ir.Instruction = block.Instructions[0]
+	ret.Span = loop.Span()
+	switch instruction.Operator {
+	case code.OpSub, code.OpAdd:
+		if instruction.Value != 1 {
+			return
+		}
+		// Record whether the loop counts up, which relies on wraparound
+		ret.Operator = code.OpClear
+		if instruction.Operator == code.OpAdd {
+			ret.Value = 1
+		}
+
+	case code.OpMoveRight:
+		// Stride is stored as int64 in Value
+		ret.Operator = code.OpScan
+		ret.Value = instruction.Value
+
+	case code.OpMoveLeft:
+		ret.Operator = code.OpScan
+		ret.Value = uint64(-int64(instruction.Value))
+
+	default:
+		return
+	}
+	return ret, true
+}
+
+// multiplyTargets returns the targets if the loop only moves its cell value to other cells, like [->+>++<<],
+// otherwise nil.
+//
+// The loop must only contain +, -, < and >, return to its cell, and decrease its cell by exactly one.
+// A target changed in both directions is refused, since the result would differ from the loop
+// when cells saturate.
+func multiplyTargets(loop *ir.Loop) (ret []code.MulTarget) {
+	if len(loop.Body) != 1 {
+		return nil
+	}
+	block, isBlock := loop.Body[0].(*ir.Block)
+	if !isBlock {
+		return nil
+	}
+
+	// Sum up changes of each cell
+	var offset int = 0
+	var changes map[int]int64 = make(map[int]int64)
+	var increased map[int]bool = make(map[int]bool)
+	var decreased map[int]bool = make(map[int]bool)
+	for _, instruction := range block.Instructions {
+		switch instruction.Operator {
+		case code.OpAdd:
+			changes[offset] += int64(instruction.Value)
+			increased[offset] = true
+
+		case code.OpSub:
+			changes[offset] -= int64(instruction.Value)
+			decreased[offset] = true
+
+		case code.OpMoveLeft:
+			offset -= int(instruction.Value)
+
+		case code.OpMoveRight:
+			offset += int(instruction.Value)
+
+		default:
+			return nil
+		}
+	}
+
+	// Check loop shape
+	if offset != 0 || changes[0] != -1 || increased[0] {
+		return nil
+	}
+	ret = make([]code.MulTarget, 0)
+	for target, factor := range changes {
+		if increased[target] && decreased[target] {
+			return nil
+		}
+		if target != 0 && factor != 0 {
+			ret = append(ret, code.MulTarget{Offset: target, Factor: factor})
+		}
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	slices.SortFunc(ret, func(x, y code.MulTarget) int { return x.Offset - y.Offset })
+	return
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codeanalyser
+
+import (
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/ir"
+)
+
+// MergePass merges runs of +-<> into one instruction, e.g. +++ becomes Add 3, and cancels opposite operators,
+// e.g. +-- becomes Sub 1 and <> is removed.
+//
+// Cancelled operators never reach a cell or tape limit, so only wrapping cells on an unbounded tape behave as -O0.
+// In debug mode runs are not merged across lines.
+type MergePass struct{}
+
+func (MergePass) Run(program *ir.Program) {
+	program.ForEachBlock(func(block *ir.Block) {
+		var merged []ir.Instruction = make([]ir.Instruction, 0, len(block.Instructions))
+		for _, instruction := range block.Instructions {
+			if len(merged) != 0 {
+				var last *ir.Instruction = &merged[len(merged)-1]
+				var sameLine bool = last.Span.EndLine == instruction.Span.Line
+				if isSimpleOperator(last.Operator) && (sameLine || !program.DebugFlag) {
+					switch {
+					case last.Operator == instruction.Operator:
+						last.Value += instruction.Value
+						last.Span = last.Span.Join(instruction.Span)
+						continue
+
+					case last.Operator == instruction.Operator.Reverse():
+						cancel(last, instruction)
+						if last.Value == 0 {
+							merged = merged[:len(merged)-1]
+						}
+						continue
+					}
+				}
+			}
+			merged = append(merged, instruction)
+		}
+		block.Instructions = merged
+	})
+}
+
+// cancel applies an opposite instruction to last, which keeps the operator of the larger one.
+func cancel(last *ir.Instruction, opposite ir.Instruction) {
+	if opposite.Value > last.Value {
+		last.Operator = opposite.Operator
+		last.Value = opposite.Value - last.Value
+	} else {
+		last.Value -= opposite.Value
+	}
+	last.Span = last.Span.Join(opposite.Span)
+}
+
+func isSimpleOperator(op code.Operator) bool {
+	switch op {
+	case code.OpAdd, code.OpSub, code.OpMoveLeft, code.OpMoveRight:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codeanalyser
+
+import (
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/ir"
+)
+
+// OffsetPass rewrites straight-line code so that +, - and clear work on cells by offset from pointer,
+// leaving one pointer move at the end of each segment. E.g. >+++>--<< becomes Add [+1] 3, Sub [+2] 2.
+//
+// Clear followed by + on the same cell becomes a single set instruction.
+//
+// Segments end at loops, input, output and scan. In debug mode they also end at line begins,
+// so every line still has its own begin index. Pointer turning back without touching a cell is moved there
+// first, so tape limits are checked as before. Outside debug mode each instruction takes the span of
+// the move that first reached its cell, so breaking tape limits is reported on the line of that move.
+type OffsetPass struct{}
+
+// reach is a pointer move reaching farther from the segment begin than any before.
+type reach struct {
+	distance int // Cells from the segment begin
+	span     ir.Span
+}
+
+func (OffsetPass) Run(program *ir.Program) {
+	program.ForEachBlock(func(block *ir.Block) {
+		block.Instructions = addressByOffset(block.Instructions, program.DebugFlag)
+	})
+}
+
+func addressByOffset(instructions []ir.Instruction, debugFlag bool) (ret []ir.Instruction) {
+	ret = make([]ir.Instruction, 0, len(instructions))
+
+	var offset int = 0       // Pointer offset not moved yet
+	var moveSpan ir.Span     // Span of the last pointer move
+	var direction int = 0    // Sign of the last pointer move in current segment
+	var touched bool = false // Whether a cell is touched since the last pointer move
+	var segmentBegin int = 0 // First index of current segment in ret
+	var rightReaches []reach // Moves reaching farther right in current segment, distance increasing
+	var leftReaches []reach  // Moves reaching farther left in current segment, distance increasing
+
+	// reachSpan returns the span of the move first reaching the cell at offset, or span if there is none
+	reachSpan := func(offset int, span ir.Span) ir.Span {
+		if debugFlag {
+			return span
+		}
+		var reaches []reach = rightReaches
+		if offset < 0 {
+			reaches, offset = leftReaches, -offset
+		}
+		for _, each := range reaches {
+			if each.distance >= offset {
+				return each.span
+			}
+		}
+		return span
+	}
+	endSegment := func() {
+		if offset > 0 {
+			ret = append(ret, ir.Instruction{Operator: code.OpMoveRight, Value: uint64(offset), Span: reachSpan(offset, moveSpan)})
+		} else if offset < 0 {
+			ret = append(ret, ir.Instruction{Operator: code.OpMoveLeft, Value: uint64(-offset), Span: reachSpan(offset, moveSpan)})
+		}
+		offset = 0
+		direction = 0
+		segmentBegin = len(ret)
+		rightReaches = rightReaches[:0]
+		leftReaches = leftReaches[:0]
+	}
+	move := func(steps int, span ir.Span) {
+		if direction != 0 && (steps > 0) != (direction > 0) && !touched {
+			endSegment()
+		}
+		offset += steps
+		moveSpan = span
+		direction = steps
+		touched = false
+
+		if offset > 0 && (len(rightReaches) == 0 || rightReaches[len(rightReaches)-1].distance < offset) {
+			rightReaches = append(rightReaches, reach{distance: offset, span: span})
+		} else if offset < 0 && (len(leftReaches) == 0 || leftReaches[len(leftReaches)-1].distance < -offset) {
+			leftReaches = append(leftReaches, reach{distance: -offset, span: span})
+		}
+	}
+
+	for index, instruction := range instructions {
+		if debugFlag && index != 0 && instruction.Span.Line != instructions[index-1].Span.EndLine {
+			endSegment()
+		}
+
+		switch instruction.Operator {
+		case code.OpMoveRight:
+			move(int(instruction.Value), instruction.Span)
+
+		case code.OpMoveLeft:
+			move(-int(instruction.Value), instruction.Span)
+
+		case code.OpAdd:
+			touched = true
+			// Merge with clear of the same cell into set
+			var last int = len(ret) - 1
+			if last >= segmentBegin && ret[last].Operator == code.OpClear && ret[last].Value == 0 && ret[last].Offset == offset {
+				ret[last].Operator = code.OpSet
+				ret[last].Value = instruction.Value
+				ret[last].Span = ret[last].Span.Join(instruction.Span)
+			} else {
+				instruction.Offset = offset
+				instruction.Span = reachSpan(offset, instruction.Span)
+				ret = append(ret, instruction)
+			}
+
+		case code.OpSub, code.OpClear:
+			touched = true
+			instruction.Offset = offset
+			instruction.Span = reachSpan(offset, instruction.Span)
+			ret = append(ret, instruction)
+
+		default:
+			endSegment()
+			ret = append(ret, instruction)
+			segmentBegin = len(ret)
+		}
+	}
+	endSegment()
+	return
+}
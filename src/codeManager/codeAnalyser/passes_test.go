@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codeanalyser_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	"github.com/Anslen/Bfck/codeManager/ir"
+)
+
+// runPass parses source and runs only the given pass on it, returning its nodes described one per item.
+//
+// An instruction is described like "Add 2 @1 1:0-1:3" with value, offset and span, a loop like
+// "Loop 1:0-1:5 [...]" with multiply targets if any.
+func runPass(t *testing.T, pass ir.Pass, source string, debugFlag bool) []string {
+	t.Helper()
+	program, err := ir.Parse(source, debugFlag)
+	if err != nil {
+		t.Fatalf("%q: %v", source, err)
+	}
+	pass.Run(program)
+	return describe(program.Body)
+}
+
+func describe(nodes []ir.Node) (ret []string) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *ir.Block:
+			for _, each := range node.Instructions {
+				ret = append(ret, fmt.Sprintf("%v %v @%v %v", each.Operator, each.Value, each.Offset, spanText(each.Span)))
+			}
+
+		case *ir.Loop:
+			var text string = fmt.Sprintf("Loop %v %v", spanText(node.Span()), describe(node.Body))
+			if node.MulTargets != nil {
+				text += fmt.Sprintf(" multiply %v", node.MulTargets)
+			}
+			ret = append(ret, text)
+		}
+	}
+	return
+}
+
+func spanText(span ir.Span) string {
+	return fmt.Sprintf("%v:%v-%v:%v", span.Line, span.Column, span.EndLine, span.EndColumn)
+}
+
+// TestPasses runs each pass alone and checks the instructions and spans it leaves.
+func TestPasses(t *testing.T) {
+	var cases = []struct {
+		name      string
+		pass      ir.Pass
+		source    string
+		debugFlag bool
+		expect    []string
+	}{
+		{"merge runs", codeanalyser.MergePass{}, "++\n+>.", false, []string{
+			"Add 3 @0 1:0-2:0", "MoveRight 1 @0 2:1-2:1", "Output 1 @0 2:2-2:2",
+		}},
+		{"merge runs in debug", codeanalyser.MergePass{}, "++\n+>.", true, []string{
+			"Add 2 @0 1:0-1:1", "Add 1 @0 2:0-2:0", "MoveRight 1 @0 2:1-2:1", "Output 1 @0 2:2-2:2",
+		}},
+		{"merge cancels", codeanalyser.MergePass{}, "+--<>.", false, []string{
+			"Sub 1 @0 1:2-1:2", "Output 1 @0 1:5-1:5",
+		}},
+		{"merge inside loop", codeanalyser.MergePass{}, "[--]", false, []string{
+			"Loop 1:0-1:3 [Sub 2 @0 1:1-1:2]",
+		}},
+		{"clear", codeanalyser.LoopPass{}, "[-]", false, []string{"Clear 0 @0 1:0-1:2"}},
+		{"clear counting up", codeanalyser.LoopPass{}, "[+]", false, []string{"Clear 1 @0 1:0-1:2"}},
+		{"scan", codeanalyser.LoopPass{}, "[<]", false, []string{"Scan 18446744073709551615 @0 1:0-1:2"}}, // Stride -1 as uint64
+		{"clear across lines", codeanalyser.LoopPass{}, "[-\n]", false, []string{"Clear 0 @0 1:0-2:0"}},
+		{"clear across lines in debug", codeanalyser.LoopPass{}, "[-\n]", true, []string{"Loop 1:0-2:0 [Sub 1 @0 1:1-1:1]"}},
+		{"multiply", codeanalyser.LoopPass{}, "[->++<]", false, []string{
+			"Loop 1:0-1:6 [Sub 1 @0 1:1-1:1 MoveRight 1 @0 1:2-1:2 Add 1 @0 1:3-1:3 Add 1 @0 1:4-1:4 MoveLeft 1 @0 1:5-1:5] multiply [{1 2}]",
+		}},
+		{"not multiply", codeanalyser.LoopPass{}, "[->+<.]", false, []string{
+			"Loop 1:0-1:6 [Sub 1 @0 1:1-1:1 MoveRight 1 @0 1:2-1:2 Add 1 @0 1:3-1:3 MoveLeft 1 @0 1:4-1:4 Output 1 @0 1:5-1:5]",
+		}},
+		{"offsets", codeanalyser.OffsetPass{}, ">+>-<<.", false, []string{
+			"Add 1 @1 1:0-1:0", "Sub 1 @2 1:2-1:2", "Output 1 @0 1:6-1:6",
+		}},
+		{"offsets in debug", codeanalyser.OffsetPass{}, ">+>-<<.", true, []string{
+			"Add 1 @1 1:1-1:1", "Sub 1 @2 1:3-1:3", "Output 1 @0 1:6-1:6",
+		}},
+		{"offsets across lines", codeanalyser.OffsetPass{}, ">\n+>", false, []string{
+			"Add 1 @1 1:0-1:0", "MoveRight 2 @0 2:1-2:1",
+		}},
+		{"offsets across lines in debug", codeanalyser.OffsetPass{}, ">\n+>", true, []string{
+			"MoveRight 1 @0 1:0-1:0", "Add 1 @0 2:0-2:0", "MoveRight 1 @0 2:1-2:1",
+		}},
+		{"turning back", codeanalyser.OffsetPass{}, ">><+", false, []string{
+			"MoveRight 2 @0 1:1-1:1", "Add 1 @-1 1:2-1:2", "MoveLeft 1 @0 1:2-1:2",
+		}},
+	}
+	for _, each := range cases {
+		if got := runPass(t, each.pass, each.source, each.debugFlag); !slices.Equal(got, each.expect) {
+			t.Errorf("%v: %q became %q, expect %q", each.name, each.source, got, each.expect)
+		}
+	}
+}
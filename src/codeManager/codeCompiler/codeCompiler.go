@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+type Target byte
+
+// Targets are the languages code can be compiled to.
+const (
+	TargetC = iota
+	TargetGo
+	TargetWasm
+	TargetAsm  // GNU as
+	TargetNasm // NASM
+	TargetLLVM
+)
+
+// Options configures compiling, zero value of each field means default.
+type Options struct {
+	Source    string               // Name of the source file, noted in output
+	TapeSize  int                  // Number of cells, addresses start from 0, default memory.ClassicTapeSize
+	CellWidth int                  // Bits of each cell, default 8
+	EOF       coderunner.EOFPolicy // Behaviour of ',' at end of input, default EOFZero
+	Package   string               // Package name of Go target, default derived from Source
+}
+
+// ParseTarget converts target name like "c" to Target.
+func ParseTarget(text string) (ret Target, err error) {
+	switch strings.ToLower(text) {
+	case "c":
+		ret = TargetC
+
+	case "go":
+		ret = TargetGo
+
+	case "wasm":
+		ret = TargetWasm
+
+	case "asm":
+		ret = TargetAsm
+
+	case "nasm":
+		ret = TargetNasm
+
+	case "llvm":
+		ret = TargetLLVM
+
+	default:
+		err = fmt.Errorf("Error: unknown target %q, expect c, go, wasm, asm, nasm or llvm", text)
+	}
+	return
+}
+
+// Extension returns the file extension of the target's output, like ".c".
+func (t Target) Extension() string {
+	switch t {
+	case TargetC:
+		return ".c"
+
+	case TargetGo:
+		return ".go"
+
+	case TargetWasm:
+		return ".wasm"
+
+	case TargetAsm:
+		return ".s"
+
+	case TargetNasm:
+		return ".asm"
+
+	case TargetLLVM:
+		return ".ll"
+	}
+	panic("codeCompiler: unknown target")
+}
+
+// Compile compiles the code for the target and writes the result to output.
+func Compile(c *code.Code, target Target, options Options, output io.Writer) (err error) {
+	// Fill defaults
+	if options.CellWidth == 0 {
+		options.CellWidth = memory.CellWidth8
+	}
+	if options.TapeSize == 0 {
+		options.TapeSize = memory.ClassicTapeSize
+	}
+	if !memory.IsValidCellWidth(options.CellWidth) {
+		return fmt.Errorf("Error: unsupported cell width %v, expect 8, 16 or 32", options.CellWidth)
+	}
+	if options.TapeSize < 0 {
+		return fmt.Errorf("Error: tape size can't be negative")
+	}
+	if options.Package == "" {
+		options.Package = packageName(options.Source)
+	}
+	if !token.IsIdentifier(options.Package) {
+		return fmt.Errorf("Error: invalid package name %q", options.Package)
+	}
+
+	var g *generator = &generator{output: new(bytes.Buffer)}
+	var result []byte
+	switch target {
+	case TargetC:
+		compileC(c, options, g)
+		result = g.output.Bytes()
+
+	case TargetGo:
+		compileGo(c, options, g)
+		result, err = format.Source(g.output.Bytes())
+		if err != nil {
+			panic("codeCompiler: generated invalid Go code, " + err.Error())
+		}
+
+	case TargetWasm:
+		if err = compileWasm(c, options, g); err != nil {
+			return
+		}
+		result = g.output.Bytes()
+
+	case TargetAsm, TargetNasm:
+		compileAsm(c, options, g, target == TargetNasm)
+		result = g.output.Bytes()
+
+	case TargetLLVM:
+		compileLLVM(c, options, g)
+		result = g.output.Bytes()
+
+	default:
+		panic("codeCompiler: unknown target")
+	}
+
+	_, err = output.Write(result)
+	return
+}
+
+// generator writes indented lines of compiled code into a buffer.
+type generator struct {
+	output     *bytes.Buffer
+	indent     int
+	indentText string
+}
+
+// line writes one line with current indent, format and args are used like fmt.Printf.
+func (g *generator) line(format string, args ...any) {
+	if format == "" {
+		g.output.WriteByte('\n')
+		return
+	}
+	for range g.indent {
+		g.output.WriteString(g.indentText)
+	}
+	fmt.Fprintf(g.output, format, args...)
+	g.output.WriteByte('\n')
+}
+
+// walk calls visit for each operator in order, loop is the number of the loop a bracket or multiply
+// operator belongs to, counted at left brackets like the L1, L2 labels printed by Code.PrintAll.
+//
+// Loops run by a multiply operator are skipped, but still counted.
+func walk(c *code.Code, visit func(index int, loop int)) {
+	var loopCount int = 0
+	var loopStack []int = make([]int, 0)
+	for index := 0; index < c.CodeCount; index++ {
+		switch c.Operators[index] {
+		case code.OpLeftBracket:
+			loopCount++
+			loopStack = append(loopStack, loopCount)
+			visit(index, loopCount)
+
+		case code.OpRightBracket:
+			visit(index, loopStack[len(loopStack)-1])
+			loopStack = loopStack[:len(loopStack)-1]
+
+		case code.OpMultiply:
+			// The following loop only contains +-<>, continue after its right bracket
+			loopCount++
+			visit(index, loopCount)
+			index = int(c.Auxiliary[index+1]) - 1
+
+		default:
+			visit(index, 0)
+		}
+	}
+}
+
+// usesInput reports whether the code contains any input operator.
+func usesInput(c *code.Code) bool {
+	for _, operator := range c.Operators {
+		if operator == code.OpInput {
+			return true
+		}
+	}
+	return false
+}
+
+// usesOutput reports whether the code contains any output operator.
+func usesOutput(c *code.Code) bool {
+	for _, operator := range c.Operators {
+		if operator == code.OpOutput {
+			return true
+		}
+	}
+	return false
+}
+
+// packageName derives a Go package name from the source file name, like "helloworld" from "Hello-World.bf".
+func packageName(source string) string {
+	var name strings.Builder
+	for _, char := range strings.ToLower(strings.TrimSuffix(source, ".bf")) {
+		if char < unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char)) {
+			name.WriteRune(char)
+		}
+	}
+	if name.Len() == 0 || unicode.IsDigit(rune(name.String()[0])) || token.IsKeyword(name.String()) {
+		return "bf" + name.String()
+	}
+	return name.String()
+}
+
+// usesTape reports whether the code reads or writes any cell, rather than only moving pointer.
+func usesTape(c *code.Code) bool {
+	for _, operator := range c.Operators {
+		if operator != code.OpMoveLeft && operator != code.OpMoveRight {
+			return true
+		}
+	}
+	return false
+}
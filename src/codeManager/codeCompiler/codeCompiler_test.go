@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// goldenPrograms are compiled and compared with testdata, together they use every operator.
+var goldenPrograms = []string{"hello", "echo", "multiply", "offsets", "scan"}
+
+// compile compiles the program with default options for the target.
+func compile(t *testing.T, each testprograms.Program, target codecompiler.Target) []byte {
+	t.Helper()
+	return compileWidth(t, each, target, 0)
+}
+
+// compileWidth compiles the program for the target with cells of the given width, 0 means default.
+func compileWidth(t *testing.T, each testprograms.Program, target codecompiler.Target, cellWidth int) []byte {
+	t.Helper()
+	c, err := codeanalyser.Analyse(each.Source, false, codeanalyser.O2)
+	if err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+	var output bytes.Buffer
+	var options codecompiler.Options = codecompiler.Options{Source: each.Name + ".bf", CellWidth: cellWidth}
+	if err = codecompiler.Compile(c, target, options, &output); err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+	return output.Bytes()
+}
+
+// checkGolden compiles golden programs for the target and compares them with testdata, or rewrites
+// testdata with -update.
+func checkGolden(t *testing.T, target codecompiler.Target) {
+	for _, name := range goldenPrograms {
+		var got []byte = compile(t, testprograms.Find(t, name), target)
+		var path string = filepath.Join("testdata", name+target.Extension())
+		if *update {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expect, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, expect) {
+			t.Errorf("%v differs from golden file, run go test -update to rewrite it after checking the change", path)
+		}
+	}
+}
+
+// runExpected runs the program on CodeRunner with the tape of compiled code and cells of the given width,
+// failed is set if it stops with a runtime error.
+func runExpected(t *testing.T, each testprograms.Program, cellWidth int) (output string, failed bool) {
+	t.Helper()
+	c, err := codeanalyser.Analyse(each.Source, false, codeanalyser.O2)
+	if err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+	var buffer bytes.Buffer
+	var options coderunner.Options = coderunner.Options{
+		Input:  strings.NewReader(each.Input),
+		Output: &buffer,
+		Memory: memory.Config{TapeSize: memory.ClassicTapeSize, CellWidth: cellWidth},
+	}
+	var ret coderunner.ReturnCode = coderunner.New(c, false, options).Run()
+	return buffer.String(), ret == coderunner.ReturnRuntimeError
+}
+
+// checkRun runs a compiled program and compares its output and exit status with CodeRunner.
+func checkRun(t *testing.T, each testprograms.Program, command *exec.Cmd) {
+	t.Helper()
+	checkRunWidth(t, each, memory.CellWidth8, command)
+}
+
+// checkRunWidth is checkRun for a program compiled with cells of the given width.
+func checkRunWidth(t *testing.T, each testprograms.Program, cellWidth int, command *exec.Cmd) {
+	t.Helper()
+	var stdout bytes.Buffer
+	command.Stdin = strings.NewReader(each.Input)
+	command.Stdout = &stdout
+	var err error = command.Run()
+	if _, exited := err.(*exec.ExitError); err != nil && !exited {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+
+	expect, failed := runExpected(t, each, cellWidth)
+	if stdout.String() != expect {
+		t.Errorf("%v %v-bit: compiled program wrote %q, CodeRunner wrote %q", each.Name, cellWidth, stdout.String(), expect)
+	}
+	if (err != nil) != failed {
+		t.Errorf("%v %v-bit: compiled program returned %v, CodeRunner failed %v", each.Name, cellWidth, err, failed)
+	}
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// asmDialect holds differences between GNU as and NASM, both in Intel syntax.
+type asmDialect struct {
+	nasm      bool
+	comment   string // Comment prefix
+	cellWidth int
+}
+
+// compileAsm writes a standalone x86-64 Linux program for GNU as or NASM, using system calls only.
+//
+// rbx holds the tape address and r12 the pointer. Loops are labelled L1 and L1_End like the
+// L1 and L1 End labels printed by Code.PrintAll, and each operator is commented as the code command shows it.
+func compileAsm(c *code.Code, options Options, g *generator, nasm bool) {
+	g.indentText = "    "
+	var d asmDialect = asmDialect{nasm: nasm, comment: "#", cellWidth: options.CellWidth}
+	if nasm {
+		d.comment = ";"
+	}
+	var name string = strings.TrimSuffix(options.Source, ".bf")
+	var cellBytes int = options.CellWidth / 8
+
+	// Header
+	g.line("%v Generated by bfck from %v: %v cells of %v bits, %v", d.comment, options.Source, options.TapeSize, options.CellWidth, eofComment(options.EOF))
+	if nasm {
+		g.line("%v Build on Linux x86-64: nasm -f elf64 %v.asm -o %v.o && ld %v.o -o %v", d.comment, name, name, name, name)
+	} else {
+		g.line("%v Build on Linux x86-64: as %v.s -o %v.o && ld %v.o -o %v", d.comment, name, name, name, name)
+	}
+	g.line("%v rbx: tape address, r12: pointer", d.comment)
+	g.line("")
+	g.indent++
+	if nasm {
+		g.line("default rel")
+		g.indent--
+		g.line("TAPE_SIZE equ %v", options.TapeSize)
+		g.indent++
+	} else {
+		g.line(".intel_syntax noprefix")
+		g.line(".equ TAPE_SIZE, %v", options.TapeSize)
+	}
+
+	// Tape and the byte used by system calls
+	g.line("")
+	d.section(g, "bss")
+	d.data(g, "tape", fmt.Sprintf("TAPE_SIZE * %v", cellBytes), "")
+	d.data(g, "io_byte", "1", "")
+
+	// Error messages, same as the runner with --tape
+	g.line("")
+	d.section(g, "rodata")
+	d.data(g, "left_message", "", "Error: pointer moved left of address 0 (address -1)")
+	d.data(g, "end_message", "", fmt.Sprintf("Error: pointer moved beyond tape end (address %v, tape size %v)", options.TapeSize, options.TapeSize))
+
+	g.line("")
+	d.section(g, "text")
+	if nasm {
+		g.line("global _start")
+	} else {
+		g.line(".globl _start")
+	}
+	g.indent--
+	g.line("_start:")
+	g.indent++
+	g.line("lea rbx, [%vtape]", d.rip())
+	g.line("xor r12d, r12d")
+
+	var lastLine int = 0
+	walk(c, func(index int, loop int) {
+		var operator code.Operator = c.Operators[index]
+		var auxiliary uint64 = c.Auxiliary[index]
+		var offset int = c.Offsets[index]
+
+		// Note source line before its first operator, then the operator itself
+		if operator != code.OpRightBracket && c.SourceLines[index] != lastLine {
+			lastLine = c.SourceLines[index]
+			g.line("")
+			g.line("%v line %v", d.comment, lastLine)
+		}
+		if operator == code.OpLeftBracket || operator == code.OpMultiply {
+			g.indent--
+			g.line("L%v:", loop)
+			g.indent++
+		} else if operator == code.OpScan {
+			g.indent--
+			g.line("scan%v:", index)
+			g.indent++
+		}
+		g.line("%v %v: %v %v", d.comment, index, operator.String(), c.FormatAuxiliary(index))
+
+		switch operator {
+		case code.OpAdd:
+			d.checkOffset(g, offset)
+			g.line("add %v, %v", d.cell(offset), asmImmediate(auxiliary, options.CellWidth))
+
+		case code.OpSub:
+			d.checkOffset(g, offset)
+			g.line("sub %v, %v", d.cell(offset), asmImmediate(auxiliary, options.CellWidth))
+
+		case code.OpMoveRight:
+			d.move(g, int64(auxiliary))
+
+		case code.OpMoveLeft:
+			d.move(g, -int64(auxiliary))
+
+		case code.OpLeftBracket:
+			g.line("cmp %v, 0", d.cell(0))
+			g.line("je L%v_End", loop)
+
+		case code.OpRightBracket:
+			g.line("jmp L%v", loop)
+			g.indent--
+			g.line("L%v_End:", loop)
+			g.indent++
+
+		case code.OpInput:
+			g.line("call read_byte")
+			var register string = asmRegister("a", options.CellWidth)
+			switch options.EOF {
+			case coderunner.EOFZero:
+				g.line("xor ecx, ecx")
+				g.line("test eax, eax")
+				g.line("cmovs eax, ecx")
+				g.line("mov %v, %v", d.cell(0), register)
+
+			case coderunner.EOFUnchanged:
+				g.line("test eax, eax")
+				g.line("js input%v_End", index)
+				g.line("mov %v, %v", d.cell(0), register)
+				g.indent--
+				g.line("input%v_End:", index)
+				g.indent++
+
+			case coderunner.EOFMinusOne:
+				// -1 is all ones of cell width
+				g.line("mov %v, %v", d.cell(0), register)
+			}
+
+		case code.OpOutput:
+			g.line("movzx eax, %v", d.cellAs(memory.CellWidth8, 0))
+			g.line("call write_byte")
+
+		case code.OpClear:
+			d.checkOffset(g, offset)
+			g.line("mov %v, 0", d.cell(offset))
+
+		case code.OpSet:
+			d.checkOffset(g, offset)
+			g.line("mov %v, %v", d.cell(offset), asmImmediate(auxiliary, options.CellWidth))
+
+		case code.OpScan:
+			g.line("cmp %v, 0", d.cell(0))
+			g.line("je scan%v_End", index)
+			d.move(g, int64(auxiliary))
+			g.line("jmp scan%v", index)
+			g.indent--
+			g.line("scan%v_End:", index)
+			g.indent++
+
+		case code.OpMultiply:
+			// Runs the loop following in one step, factors wrap like the cell does
+			if options.CellWidth == memory.CellWidth32 {
+				g.line("mov eax, %v", d.cell(0))
+			} else {
+				g.line("movzx eax, %v", d.cell(0))
+			}
+			g.line("test eax, eax")
+			g.line("jz L%v_End", loop)
+			for _, target := range c.MulTargets[auxiliary] {
+				var operation string = "add"
+				var factor uint64 = uint64(target.Factor)
+				if target.Factor < 0 {
+					operation = "sub"
+					factor = uint64(-target.Factor)
+				}
+				d.checkOffset(g, target.Offset)
+				if factor&memory.CellMax(options.CellWidth) == 1 {
+					g.line("%v %v, %v", operation, d.cell(target.Offset), asmRegister("a", options.CellWidth))
+				} else {
+					g.line("imul ecx, eax, %v", asmImmediate(factor, options.CellWidth))
+					g.line("%v %v, %v", operation, d.cell(target.Offset), asmRegister("c", options.CellWidth))
+				}
+			}
+			g.line("mov %v, 0", d.cell(0))
+			g.indent--
+			g.line("L%v_End:", loop)
+			g.indent++
+		}
+	})
+
+	// Exit with status 0
+	g.line("")
+	g.line("mov eax, 60")
+	g.line("xor edi, edi")
+	g.line("syscall")
+
+	// Helpers
+	if usesInput(c) {
+		g.line("")
+		g.indent--
+		g.line("%v read_byte sets eax to the next byte of stdin, or -1 at end of input.", d.comment)
+		g.line("read_byte:")
+		g.indent++
+		g.line("xor eax, eax")
+		g.line("xor edi, edi")
+		g.line("lea rsi, [%vio_byte]", d.rip())
+		g.line("mov edx, 1")
+		g.line("syscall")
+		g.line("cmp rax, 1")
+		g.line("jne read_eof")
+		g.line("movzx eax, %v [%vio_byte]", d.size(memory.CellWidth8), d.rip())
+		g.line("ret")
+		g.indent--
+		g.line("read_eof:")
+		g.indent++
+		g.line("mov eax, -1")
+		g.line("ret")
+	}
+	if usesOutput(c) {
+		g.line("")
+		g.indent--
+		g.line("%v write_byte writes al to stdout.", d.comment)
+		g.line("write_byte:")
+		g.indent++
+		g.line("mov %v [%vio_byte], al", d.size(memory.CellWidth8), d.rip())
+		g.line("mov eax, 1")
+		g.line("mov edi, 1")
+		g.line("lea rsi, [%vio_byte]", d.rip())
+		g.line("mov edx, 1")
+		g.line("syscall")
+		g.line("ret")
+	}
+
+	// Errors write a message to stderr and exit with status 1
+	g.line("")
+	g.indent--
+	g.line("left_edge:")
+	g.indent++
+	g.line("lea rsi, [%vleft_message]", d.rip())
+	g.line("mov edx, left_message_length")
+	g.line("jmp fail")
+	g.indent--
+	g.line("tape_end:")
+	g.indent++
+	g.line("lea rsi, [%vend_message]", d.rip())
+	g.line("mov edx, end_message_length")
+	g.indent--
+	g.line("fail:")
+	g.indent++
+	g.line("mov eax, 1")
+	g.line("mov edi, 2")
+	g.line("syscall")
+	g.line("mov eax, 60")
+	g.line("mov edi, 1")
+	g.line("syscall")
+	g.indent--
+}
+
+// section starts a section like text or bss.
+func (d asmDialect) section(g *generator, name string) {
+	if d.nasm {
+		g.line("section .%v", name)
+	} else if name == "rodata" {
+		g.line(".section .rodata")
+	} else {
+		g.line(".%v", name)
+	}
+}
+
+// data defines label with size bytes of zeros, or with the message followed by a new line and
+// a label_length constant if message is not empty.
+func (d asmDialect) data(g *generator, label string, size string, message string) {
+	g.indent--
+	g.line("%v:", label)
+	g.indent++
+	switch {
+	case message == "" && d.nasm:
+		g.line("resb %v", size)
+
+	case message == "":
+		g.line(".zero %v", size)
+
+	case d.nasm:
+		g.line("db \"%v\", 10", message)
+		g.indent--
+		g.line("%v_length equ $ - %v", label, label)
+		g.indent++
+
+	default:
+		g.line(".ascii \"%v\\n\"", message)
+		g.line(".equ %v_length, . - %v", label, label)
+	}
+}
+
+// rip returns the prefix of RIP relative addresses, which NASM adds by default rel.
+func (d asmDialect) rip() string {
+	if d.nasm {
+		return ""
+	}
+	return "rip + "
+}
+
+// size returns the operand size keyword of a memory operand of width bits.
+func (d asmDialect) size(width int) (ret string) {
+	switch width {
+	case memory.CellWidth8:
+		ret = "byte"
+	case memory.CellWidth16:
+		ret = "word"
+	default:
+		ret = "dword"
+	}
+	if !d.nasm {
+		ret += " ptr"
+	}
+	return
+}
+
+// cell returns the memory operand of the cell at offset from pointer.
+func (d asmDialect) cell(offset int) string {
+	return d.cellAs(d.cellWidth, offset)
+}
+
+// cellAs returns the memory operand of the cell at offset from pointer, accessing its lowest width bits.
+func (d asmDialect) cellAs(width int, offset int) string {
+	var address string = "rbx + r12"
+	var cellBytes int = d.cellWidth / 8
+	if d.cellWidth != memory.CellWidth8 {
+		address = fmt.Sprintf("rbx + r12*%v", cellBytes)
+	}
+	if offset > 0 {
+		address += fmt.Sprintf(" + %v", offset*cellBytes)
+	} else if offset < 0 {
+		address += fmt.Sprintf(" - %v", -offset*cellBytes)
+	}
+	return fmt.Sprintf("%v [%v]", d.size(width), address)
+}
+
+// move moves pointer by steps cells, stopping at left_edge or tape_end if it leaves the tape.
+func (d asmDialect) move(g *generator, steps int64) {
+	if steps > 0 {
+		g.line("add r12, %v", steps)
+		g.line("cmp r12, TAPE_SIZE")
+		g.line("jge tape_end")
+	} else {
+		g.line("sub r12, %v", -steps)
+		g.line("jl left_edge")
+	}
+}
+
+// checkOffset stops the program if the cell at offset from pointer is outside the tape.
+func (d asmDialect) checkOffset(g *generator, offset int) {
+	if offset > 0 {
+		g.line("lea rdx, [r12 + %v]", offset)
+		g.line("cmp rdx, TAPE_SIZE")
+		g.line("jge tape_end")
+	} else if offset < 0 {
+		g.line("lea rdx, [r12 - %v]", -offset)
+		g.line("test rdx, rdx")
+		g.line("js left_edge")
+	}
+}
+
+// asmImmediate returns value truncated to cell width, as signed if it doesn't fit in a signed dword.
+func asmImmediate(value uint64, cellWidth int) string {
+	value &= memory.CellMax(cellWidth)
+	if value > 0x7FFFFFFF {
+		return fmt.Sprintf("%d", int32(value))
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+// asmRegister returns the part of rax or rcx, named by letter "a" or "c", with width bits.
+func asmRegister(letter string, width int) string {
+	switch width {
+	case memory.CellWidth8:
+		return letter + "l"
+	case memory.CellWidth16:
+		return letter + "x"
+	}
+	return "e" + letter + "x"
+}
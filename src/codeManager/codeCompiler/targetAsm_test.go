@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+func TestAsmGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetAsm)
+}
+
+func TestNasmGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetNasm)
+}
+
+// TestAsmRun assembles every program with GNU as for each cell width, links it with ld and compares
+// running it with CodeRunner.
+func TestAsmRun(t *testing.T) {
+	checkAsmRun(t, codecompiler.TargetAsm, "as")
+}
+
+// TestNasmRun is TestAsmRun with NASM.
+func TestNasmRun(t *testing.T) {
+	checkAsmRun(t, codecompiler.TargetNasm, "nasm", "-f", "elf64")
+}
+
+// checkAsmRun builds programs for the target with the assembler command, then links and runs them.
+func checkAsmRun(t *testing.T, target codecompiler.Target, name string, flags ...string) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("compiled code runs on Linux x86-64 only")
+	}
+	assembler, err := exec.LookPath(name)
+	if err != nil {
+		t.Skipf("no %v found", name)
+	}
+	linker, err := exec.LookPath("ld")
+	if err != nil {
+		t.Skip("no ld found")
+	}
+
+	var dir string = t.TempDir()
+	for _, each := range testprograms.Load(t) {
+		for _, cellWidth := range []int{memory.CellWidth8, memory.CellWidth16, memory.CellWidth32} {
+			var program string = fmt.Sprintf("%v_%v", each.Name, cellWidth)
+			var source string = filepath.Join(dir, program+target.Extension())
+			var object string = filepath.Join(dir, program+".o")
+			var binary string = filepath.Join(dir, program)
+			if err = os.WriteFile(source, compileWidth(t, each, target, cellWidth), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if output, err := exec.Command(assembler, append(flags, source, "-o", object)...).CombinedOutput(); err != nil {
+				t.Fatalf("%v: %v failed: %v\n%s", program, filepath.Base(assembler), err, output)
+			}
+			if output, err := exec.Command(linker, object, "-o", binary).CombinedOutput(); err != nil {
+				t.Fatalf("%v: ld failed: %v\n%s", program, err, output)
+			}
+			checkRunWidth(t, each, cellWidth, exec.Command(binary))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"fmt"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// compileC writes a standalone C program, the tape is an array indexed by p.
+//
+// Moving out of the tape stops the program with an error like the runner with --tape.
+func compileC(c *code.Code, options Options, g *generator) {
+	g.indentText = "    "
+	var mask uint64 = memory.CellMax(options.CellWidth)
+
+	// Header and tape
+	g.line("/* Generated by bfck from %v: %v cells of %v bits, %v */", options.Source, options.TapeSize, options.CellWidth, eofComment(options.EOF))
+	g.line("")
+	g.line("#include <stdint.h>")
+	g.line("#include <stdio.h>")
+	g.line("#include <stdlib.h>")
+	g.line("")
+	g.line("#define TAPE_SIZE %v", options.TapeSize)
+	g.line("")
+	g.line("typedef uint%v_t cell;", options.CellWidth)
+	g.line("")
+	if usesTape(c) {
+		g.line("static cell tape[TAPE_SIZE];")
+		g.line("")
+	}
+	g.line("/* at returns the tape index, stopping the program if it is outside the tape. */")
+	g.line("static inline long at(long index) {")
+	g.line("    if (index < 0) {")
+	g.line("        fprintf(stderr, \"Error: pointer moved left of address 0 (address -1)\\n\");")
+	g.line("        exit(1);")
+	g.line("    }")
+	g.line("    if (index >= TAPE_SIZE) {")
+	g.line("        fprintf(stderr, \"Error: pointer moved beyond tape end (address %%d, tape size %%d)\\n\", TAPE_SIZE, TAPE_SIZE);")
+	g.line("        exit(1);")
+	g.line("    }")
+	g.line("    return index;")
+	g.line("}")
+	g.line("")
+	g.line("int main(void) {")
+	g.indent++
+	g.line("long p = 0;")
+	if usesInput(c) {
+		g.line("int input;")
+	}
+
+	var lastLine int = 0
+	walk(c, func(index int, loop int) {
+		var operator code.Operator = c.Operators[index]
+		var auxiliary uint64 = c.Auxiliary[index]
+		var target string = cCell(c.Offsets[index])
+
+		// Note source line before its first statement
+		if operator != code.OpRightBracket && c.SourceLines[index] != lastLine {
+			lastLine = c.SourceLines[index]
+			g.line("")
+			g.line("/* line %v */", lastLine)
+		}
+
+		switch operator {
+		case code.OpAdd:
+			g.line("%v += %v;", target, auxiliary&mask)
+
+		case code.OpSub:
+			g.line("%v -= %v;", target, auxiliary&mask)
+
+		case code.OpMoveRight:
+			g.line("p = at(p + %v);", auxiliary)
+
+		case code.OpMoveLeft:
+			g.line("p = at(p - %v);", auxiliary)
+
+		case code.OpLeftBracket:
+			g.line("while (tape[p]) { /* L%v */", loop)
+			g.indent++
+
+		case code.OpRightBracket:
+			g.indent--
+			g.line("} /* L%v End */", loop)
+
+		case code.OpInput:
+			g.line("fflush(stdout);")
+			g.line("input = getchar();")
+			switch options.EOF {
+			case coderunner.EOFZero:
+				g.line("tape[p] = input == EOF ? 0 : (cell)input;")
+
+			case coderunner.EOFUnchanged:
+				g.line("if (input != EOF) tape[p] = (cell)input;")
+
+			case coderunner.EOFMinusOne:
+				g.line("tape[p] = input == EOF ? (cell)-1 : (cell)input;")
+			}
+
+		case code.OpOutput:
+			g.line("putchar(tape[p]);")
+
+		case code.OpClear:
+			g.line("%v = 0;", target)
+
+		case code.OpSet:
+			g.line("%v = %v;", target, auxiliary&mask)
+
+		case code.OpScan:
+			var stride int64 = int64(auxiliary)
+			if stride > 0 {
+				g.line("while (tape[p]) p = at(p + %v);", stride)
+			} else {
+				g.line("while (tape[p]) p = at(p - %v);", -stride)
+			}
+
+		case code.OpMultiply:
+			// Unsigned factors keep the multiplication wrapping
+			g.line("if (tape[p]) { /* L%v, multiply */", loop)
+			for _, mulTarget := range c.MulTargets[auxiliary] {
+				var operation string = "+="
+				var factor uint64 = uint64(mulTarget.Factor)
+				if mulTarget.Factor < 0 {
+					operation = "-="
+					factor = uint64(-mulTarget.Factor)
+				}
+				if factor&mask == 1 {
+					g.line("    %v %v tape[p];", cCell(mulTarget.Offset), operation)
+				} else {
+					g.line("    %v %v tape[p] * %vu;", cCell(mulTarget.Offset), operation, factor&mask)
+				}
+			}
+			g.line("    tape[p] = 0;")
+			g.line("} /* L%v End */", loop)
+		}
+	})
+
+	g.line("")
+	g.line("return 0;")
+	g.indent--
+	g.line("}")
+}
+
+// cCell returns the C expression of the cell at offset from pointer.
+func cCell(offset int) string {
+	if offset > 0 {
+		return fmt.Sprintf("tape[at(p + %v)]", offset)
+	} else if offset < 0 {
+		return fmt.Sprintf("tape[at(p - %v)]", -offset)
+	}
+	return "tape[p]"
+}
+
+// eofComment describes the EOF policy for generated comments.
+func eofComment(policy coderunner.EOFPolicy) string {
+	switch policy {
+	case coderunner.EOFUnchanged:
+		return "',' leaves cell unchanged at end of input"
+
+	case coderunner.EOFMinusOne:
+		return "',' stores -1 at end of input"
+	}
+	return "',' stores 0 at end of input"
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	"github.com/Anslen/Bfck/internal/testprograms"
+)
+
+func TestCGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetC)
+}
+
+// TestCRun compiles every program with the system C compiler and compares running it with CodeRunner.
+func TestCRun(t *testing.T) {
+	compiler, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler found")
+	}
+
+	var dir string = t.TempDir()
+	for _, each := range testprograms.Load(t) {
+		var source string = filepath.Join(dir, each.Name+".c")
+		var binary string = filepath.Join(dir, each.Name)
+		if err = os.WriteFile(source, compile(t, each, codecompiler.TargetC), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if output, err := exec.Command(compiler, "-O1", "-o", binary, source).CombinedOutput(); err != nil {
+			t.Fatalf("%v: cc failed: %v\n%s", each.Name, err, output)
+		}
+		checkRun(t, each, exec.Command(binary))
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"fmt"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// compileGo writes a Go package exposing Run(in io.Reader, out io.Writer) error, the result is formatted by caller.
+//
+// Moving out of the tape makes Run return an error wrapping ErrLeftEdge or ErrTapeEnd.
+func compileGo(c *code.Code, options Options, g *generator) {
+	g.indentText = "\t"
+	var mask uint64 = memory.CellMax(options.CellWidth)
+	var hasInput bool = usesInput(c)
+	var hasTape bool = usesTape(c)
+
+	// Header, errors and helpers
+	g.line("// Code generated by bfck from %v. DO NOT EDIT.", options.Source)
+	g.line("")
+	g.line("// Package %v runs %v with %v cells of %v bits, %v.", options.Package, options.Source, options.TapeSize, options.CellWidth, eofComment(options.EOF))
+	g.line("package %v", options.Package)
+	g.line("")
+	g.line("import (")
+	g.line("\t\"bufio\"")
+	g.line("\t\"errors\"")
+	g.line("\t\"fmt\"")
+	g.line("\t\"io\"")
+	g.line(")")
+	g.line("")
+	g.line("const tapeSize = %v", options.TapeSize)
+	g.line("")
+	g.line("type cell = uint%v", options.CellWidth)
+	g.line("")
+	g.line("var (")
+	g.line("ErrLeftEdge = errors.New(\"pointer moved left of address 0\")")
+	g.line("ErrTapeEnd  = errors.New(\"pointer moved beyond tape end\")")
+	g.line(")")
+	g.line("")
+	g.line("// tapeError is raised by at and returned by Run.")
+	g.line("type tapeError struct {")
+	g.line("err error")
+	g.line("}")
+	g.line("")
+	g.line("// at returns the tape index, raising tapeError if it is outside the tape.")
+	g.line("func at(index int) int {")
+	g.line("if index < 0 {")
+	g.line("panic(tapeError{fmt.Errorf(\"%%w (address -1)\", ErrLeftEdge)})")
+	g.line("}")
+	g.line("if index >= tapeSize {")
+	g.line("panic(tapeError{fmt.Errorf(\"%%w (address %%v, tape size %%v)\", ErrTapeEnd, tapeSize, tapeSize)})")
+	g.line("}")
+	g.line("return index")
+	g.line("}")
+	if hasInput {
+		g.line("")
+		g.line("// read returns the next input byte as a cell, current is the cell value before reading.")
+		g.line("func read(reader *bufio.Reader, current cell) (cell, error) {")
+		g.line("value, err := reader.ReadByte()")
+		g.line("if err == io.EOF {")
+		switch options.EOF {
+		case coderunner.EOFZero:
+			g.line("return 0, nil")
+
+		case coderunner.EOFUnchanged:
+			g.line("return current, nil")
+
+		case coderunner.EOFMinusOne:
+			g.line("return ^cell(0), nil")
+		}
+		g.line("}")
+		g.line("return cell(value), err")
+		g.line("}")
+	}
+
+	// Run function
+	g.line("")
+	g.line("// Run runs the program, reading ',' from in and writing '.' to out.")
+	g.line("func Run(in io.Reader, out io.Writer) (err error) {")
+	g.indent++
+	if hasTape {
+		g.line("var tape []cell = make([]cell, tapeSize)")
+	}
+	g.line("var p int = 0")
+	if hasInput {
+		g.line("var reader *bufio.Reader = bufio.NewReader(in)")
+	}
+	g.line("var writer *bufio.Writer = bufio.NewWriter(out)")
+	g.line("defer func() {")
+	g.line("\tif flushErr := writer.Flush(); err == nil {")
+	g.line("\t\terr = flushErr")
+	g.line("\t}")
+	g.line("\tif recovered := recover(); recovered != nil {")
+	g.line("\t\tif tapeErr, isTapeError := recovered.(tapeError); isTapeError {")
+	g.line("\t\t\terr = tapeErr.err")
+	g.line("\t\t} else {")
+	g.line("\t\t\tpanic(recovered)")
+	g.line("\t\t}")
+	g.line("\t}")
+	g.line("}()")
+
+	var lastLine int = 0
+	walk(c, func(index int, loop int) {
+		var operator code.Operator = c.Operators[index]
+		var auxiliary uint64 = c.Auxiliary[index]
+		var target string = goCell(c.Offsets[index])
+
+		// Note source line before its first statement
+		if operator != code.OpRightBracket && c.SourceLines[index] != lastLine {
+			lastLine = c.SourceLines[index]
+			g.line("")
+			g.line("// line %v", lastLine)
+		}
+
+		switch operator {
+		case code.OpAdd:
+			g.line("%v += %v", target, auxiliary&mask)
+
+		case code.OpSub:
+			g.line("%v -= %v", target, auxiliary&mask)
+
+		case code.OpMoveRight:
+			g.line("p = at(p + %v)", auxiliary)
+
+		case code.OpMoveLeft:
+			g.line("p = at(p - %v)", auxiliary)
+
+		case code.OpLeftBracket:
+			g.line("for tape[p] != 0 { // L%v", loop)
+			g.indent++
+
+		case code.OpRightBracket:
+			g.indent--
+			g.line("} // L%v End", loop)
+
+		case code.OpInput:
+			g.line("if err = writer.Flush(); err != nil {")
+			g.line("\treturn")
+			g.line("}")
+			g.line("if tape[p], err = read(reader, tape[p]); err != nil {")
+			g.line("\treturn")
+			g.line("}")
+
+		case code.OpOutput:
+			g.line("writer.WriteByte(byte(tape[p]))")
+
+		case code.OpClear:
+			g.line("%v = 0", target)
+
+		case code.OpSet:
+			g.line("%v = %v", target, auxiliary&mask)
+
+		case code.OpScan:
+			var stride int64 = int64(auxiliary)
+			g.line("for tape[p] != 0 {")
+			if stride > 0 {
+				g.line("\tp = at(p + %v)", stride)
+			} else {
+				g.line("\tp = at(p - %v)", -stride)
+			}
+			g.line("}")
+
+		case code.OpMultiply:
+			g.line("if tape[p] != 0 { // L%v, multiply", loop)
+			for _, mulTarget := range c.MulTargets[auxiliary] {
+				var operation string = "+="
+				var factor uint64 = uint64(mulTarget.Factor)
+				if mulTarget.Factor < 0 {
+					operation = "-="
+					factor = uint64(-mulTarget.Factor)
+				}
+				if factor&mask == 1 {
+					g.line("\t%v %v tape[p]", goCell(mulTarget.Offset), operation)
+				} else {
+					g.line("\t%v %v tape[p] * %v", goCell(mulTarget.Offset), operation, factor&mask)
+				}
+			}
+			g.line("\ttape[p] = 0")
+			g.line("} // L%v End", loop)
+		}
+	})
+
+	g.line("")
+	g.line("return nil")
+	g.indent--
+	g.line("}")
+}
+
+// goCell returns the Go expression of the cell at offset from pointer.
+func goCell(offset int) string {
+	if offset > 0 {
+		return fmt.Sprintf("tape[at(p+%v)]", offset)
+	} else if offset < 0 {
+		return fmt.Sprintf("tape[at(p-%v)]", -offset)
+	}
+	return "tape[p]"
+}
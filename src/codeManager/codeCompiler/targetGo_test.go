@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	"github.com/Anslen/Bfck/internal/testprograms"
+)
+
+func TestGoGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetGo)
+}
+
+// TestGoRun builds every program as a package of one module with a command running the package
+// named by its argument, then compares running it with CodeRunner.
+func TestGoRun(t *testing.T) {
+	goCommand, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("no go command found")
+	}
+
+	var dir string = t.TempDir()
+	var programs []testprograms.Program = testprograms.Load(t)
+	var imports, cases strings.Builder
+	for _, each := range programs {
+		var packageDir string = filepath.Join(dir, each.Name)
+		if err = os.Mkdir(packageDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(packageDir, each.Name+".go"), compile(t, each, codecompiler.TargetGo), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&imports, "\t%v %q\n", each.Name, "bftest/"+each.Name)
+		fmt.Fprintf(&cases, "\tcase %q:\n\t\terr = %v.Run(os.Stdin, os.Stdout)\n", each.Name, each.Name)
+	}
+
+	var main string = "package main\n\nimport (\n\t\"os\"\n\n" + imports.String() + ")\n\n" +
+		"func main() {\n\tvar err error\n\tswitch os.Args[1] {\n" + cases.String() + "\t}\n" +
+		"\tif err != nil {\n\t\tos.Stderr.WriteString(err.Error() + \"\\n\")\n\t\tos.Exit(1)\n\t}\n}\n"
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module bftest\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var binary string = filepath.Join(dir, "bftest")
+	var build *exec.Cmd = exec.Command(goCommand, "build", "-o", binary, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOTOOLCHAIN=local", "GOFLAGS=")
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, output)
+	}
+	for _, each := range programs {
+		checkRun(t, each, exec.Command(binary, each.Name))
+	}
+}
@@ -0,0 +1,336 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// compileLLVM writes a module in LLVM IR text with a main function, using the C library for I/O.
+//
+// The pointer lives in %p, which LLVM promotes to a register when optimising. Loops are blocks
+// labelled L1, L1.body and L1.end, matching the L1 and L1 End labels printed by Code.PrintAll.
+func compileLLVM(c *code.Code, options Options, g *generator) {
+	g.indentText = "  "
+	var name string = strings.TrimSuffix(options.Source, ".bf")
+	var w *llvmWriter = &llvmWriter{
+		g:        g,
+		cell:     fmt.Sprintf("i%v", options.CellWidth),
+		tape:     fmt.Sprintf("[%v x i%v]", options.TapeSize, options.CellWidth),
+		tapeSize: options.TapeSize,
+		width:    options.CellWidth,
+	}
+	var leftMessage string = "Error: pointer moved left of address 0 (address -1)\n"
+	var endMessage string = fmt.Sprintf("Error: pointer moved beyond tape end (address %v, tape size %v)\n", options.TapeSize, options.TapeSize)
+
+	// Header, tape and messages
+	g.line("; Generated by bfck from %v: %v cells of %v bits, %v", options.Source, options.TapeSize, options.CellWidth, eofComment(options.EOF))
+	g.line("; Build: clang -O2 %v.ll -o %v", name, name)
+	g.line("source_filename = %v", llvmString(options.Source))
+	g.line("")
+	g.line("@tape = internal global %v zeroinitializer", w.tape)
+	g.line("@left_message = private constant [%v x i8] c%v", len(leftMessage), llvmString(leftMessage))
+	g.line("@end_message = private constant [%v x i8] c%v", len(endMessage), llvmString(endMessage))
+	g.line("")
+	g.line("declare i32 @getchar()")
+	g.line("declare i32 @putchar(i32)")
+	g.line("declare i32 @fflush(ptr)")
+	g.line("declare i64 @write(i32, ptr, i64)")
+	g.line("declare void @exit(i32) noreturn")
+	g.line("")
+
+	// at returns the tape index, stopping the program if it is outside the tape
+	g.line("; at returns the tape index, stopping the program if it is outside the tape.")
+	g.line("define internal i64 @at(i64 %%index) {")
+	g.line("entry:")
+	g.line("  %%left = icmp slt i64 %%index, 0")
+	g.line("  br i1 %%left, label %%left_edge, label %%check_end")
+	g.line("check_end:")
+	g.line("  %%end = icmp sge i64 %%index, %v", options.TapeSize)
+	g.line("  br i1 %%end, label %%tape_end, label %%inside")
+	g.line("inside:")
+	g.line("  ret i64 %%index")
+	g.line("left_edge:")
+	g.line("  call void @fail(ptr @left_message, i64 %v)", len(leftMessage))
+	g.line("  unreachable")
+	g.line("tape_end:")
+	g.line("  call void @fail(ptr @end_message, i64 %v)", len(endMessage))
+	g.line("  unreachable")
+	g.line("}")
+	g.line("")
+	g.line("; fail writes message to stderr and exits with status 1, flushing output.")
+	g.line("define internal void @fail(ptr %%message, i64 %%length) noreturn {")
+	g.line("entry:")
+	g.line("  %%written = call i64 @write(i32 2, ptr %%message, i64 %%length)")
+	g.line("  call void @exit(i32 1)")
+	g.line("  unreachable")
+	g.line("}")
+	g.line("")
+
+	g.line("define i32 @main() {")
+	g.line("entry:")
+	g.indent++
+	g.line("%%p = alloca i64")
+	g.line("store i64 0, ptr %%p")
+
+	var lastLine int = 0
+	walk(c, func(index int, loop int) {
+		var operator code.Operator = c.Operators[index]
+		var auxiliary uint64 = c.Auxiliary[index]
+		var offset int = c.Offsets[index]
+
+		// Note source line before its first operator, then the operator itself
+		if operator != code.OpRightBracket && c.SourceLines[index] != lastLine {
+			lastLine = c.SourceLines[index]
+			g.line("")
+			g.line("; line %v", lastLine)
+		}
+		g.line("; %v: %v %v", index, operator.String(), c.FormatAuxiliary(index))
+
+		switch operator {
+		case code.OpAdd:
+			w.update(offset, "add", w.constant(auxiliary))
+
+		case code.OpSub:
+			w.update(offset, "sub", w.constant(auxiliary))
+
+		case code.OpMoveRight:
+			w.move("add", auxiliary)
+
+		case code.OpMoveLeft:
+			w.move("sub", auxiliary)
+
+		case code.OpLeftBracket:
+			w.label(fmt.Sprintf("L%v", loop))
+			var condition string = w.nonZero(w.load(w.address(0)))
+			w.branch("br i1 %v, label %%L%v.body, label %%L%v.end", condition, loop, loop)
+			w.label(fmt.Sprintf("L%v.body", loop))
+
+		case code.OpRightBracket:
+			w.branch("br label %%L%v", loop)
+			w.label(fmt.Sprintf("L%v.end", loop))
+
+		case code.OpInput:
+			g.line("call i32 @fflush(ptr null)")
+			var input string = w.temp()
+			g.line("%v = call i32 @getchar()", input)
+			var eof string = w.temp()
+			g.line("%v = icmp eq i32 %v, -1", eof, input)
+			var address string = w.address(0)
+			var value string = w.temp()
+			if w.width == memory.CellWidth32 {
+				value = input
+			} else {
+				g.line("%v = trunc i32 %v to %v", value, input, w.cell)
+			}
+			switch options.EOF {
+			case coderunner.EOFZero:
+				var selected string = w.temp()
+				g.line("%v = select i1 %v, %v 0, %v %v", selected, eof, w.cell, w.cell, value)
+				value = selected
+
+			case coderunner.EOFUnchanged:
+				var current string = w.load(address)
+				var selected string = w.temp()
+				g.line("%v = select i1 %v, %v %v, %v %v", selected, eof, w.cell, current, w.cell, value)
+				value = selected
+
+			case coderunner.EOFMinusOne:
+				// -1 is truncated to all ones of cell width
+			}
+			g.line("store %v %v, ptr %v", w.cell, value, address)
+
+		case code.OpOutput:
+			var value string = w.load(w.address(0))
+			if w.width != memory.CellWidth32 {
+				var extended string = w.temp()
+				g.line("%v = zext %v %v to i32", extended, w.cell, value)
+				value = extended
+			}
+			g.line("call i32 @putchar(i32 %v)", value)
+
+		case code.OpClear:
+			g.line("store %v 0, ptr %v", w.cell, w.address(offset))
+
+		case code.OpSet:
+			g.line("store %v %v, ptr %v", w.cell, w.constant(auxiliary), w.address(offset))
+
+		case code.OpScan:
+			w.label(fmt.Sprintf("scan%v", index))
+			var condition string = w.nonZero(w.load(w.address(0)))
+			w.branch("br i1 %v, label %%scan%v.body, label %%scan%v.end", condition, index, index)
+			w.label(fmt.Sprintf("scan%v.body", index))
+			var stride int64 = int64(auxiliary)
+			if stride > 0 {
+				w.move("add", uint64(stride))
+			} else {
+				w.move("sub", uint64(-stride))
+			}
+			w.branch("br label %%scan%v", index)
+			w.label(fmt.Sprintf("scan%v.end", index))
+
+		case code.OpMultiply:
+			// Runs the loop following in one step, multiplication wraps like the cell does
+			w.label(fmt.Sprintf("L%v", loop))
+			var count string = w.load(w.address(0))
+			var condition string = w.nonZero(count)
+			w.branch("br i1 %v, label %%L%v.body, label %%L%v.end", condition, loop, loop)
+			w.label(fmt.Sprintf("L%v.body", loop))
+			for _, target := range c.MulTargets[auxiliary] {
+				var operation string = "add"
+				var factor uint64 = uint64(target.Factor)
+				if target.Factor < 0 {
+					operation = "sub"
+					factor = uint64(-target.Factor)
+				}
+				var product string = count
+				if factor&memory.CellMax(w.width) != 1 {
+					product = w.temp()
+					g.line("%v = mul %v %v, %v", product, w.cell, count, w.constant(factor))
+				}
+				w.update(target.Offset, operation, product)
+			}
+			g.line("store %v 0, ptr %v", w.cell, w.address(0))
+			w.label(fmt.Sprintf("L%v.end", loop))
+		}
+	})
+
+	g.line("")
+	g.line("ret i32 0")
+	g.indent--
+	g.line("}")
+}
+
+// llvmWriter writes instructions of main, naming temporary values %t1, %t2 and so on.
+type llvmWriter struct {
+	g          *generator
+	cell       string // Type of cell, like i8
+	tape       string // Type of tape, like [30000 x i8]
+	tapeSize   int
+	width      int
+	temps      int
+	terminated bool // Current block ends with a branch
+}
+
+// temp returns the name of a new temporary value.
+func (w *llvmWriter) temp() string {
+	w.temps++
+	return fmt.Sprintf("%%t%v", w.temps)
+}
+
+// branch writes a branch instruction ending the current block, format and args are used like fmt.Printf.
+func (w *llvmWriter) branch(format string, args ...any) {
+	w.g.line(format, args...)
+	w.terminated = true
+}
+
+// label starts a new block, falling through from the current block if it isn't terminated yet.
+func (w *llvmWriter) label(label string) {
+	if !w.terminated {
+		w.g.line("br label %%%v", label)
+	}
+	w.g.indent--
+	w.g.line("%v:", label)
+	w.g.indent++
+	w.terminated = false
+}
+
+// address returns the address of the cell at offset from pointer, checking tape like at in C target.
+func (w *llvmWriter) address(offset int) string {
+	var pointer string = w.temp()
+	w.g.line("%v = load i64, ptr %%p", pointer)
+	if offset != 0 {
+		var index string = w.temp()
+		if offset > 0 {
+			w.g.line("%v = add i64 %v, %v", index, pointer, offset)
+		} else {
+			w.g.line("%v = sub i64 %v, %v", index, pointer, -offset)
+		}
+		pointer = w.temp()
+		w.g.line("%v = call i64 @at(i64 %v)", pointer, index)
+	}
+	var address string = w.temp()
+	w.g.line("%v = getelementptr inbounds %v, ptr @tape, i64 0, i64 %v", address, w.tape, pointer)
+	return address
+}
+
+// load returns the value of the cell at address.
+func (w *llvmWriter) load(address string) string {
+	var value string = w.temp()
+	w.g.line("%v = load %v, ptr %v", value, w.cell, address)
+	return value
+}
+
+// nonZero returns an i1 value telling whether value is not zero.
+func (w *llvmWriter) nonZero(value string) string {
+	var condition string = w.temp()
+	w.g.line("%v = icmp ne %v %v, 0", condition, w.cell, value)
+	return condition
+}
+
+// update applies operation like add with operand to the cell at offset from pointer.
+func (w *llvmWriter) update(offset int, operation string, operand string) {
+	var address string = w.address(offset)
+	var value string = w.load(address)
+	var result string = w.temp()
+	w.g.line("%v = %v %v %v, %v", result, operation, w.cell, value, operand)
+	w.g.line("store %v %v, ptr %v", w.cell, result, address)
+}
+
+// move applies operation add or sub with steps to pointer, stopping the program if it leaves the tape.
+func (w *llvmWriter) move(operation string, steps uint64) {
+	var pointer string = w.temp()
+	w.g.line("%v = load i64, ptr %%p", pointer)
+	var moved string = w.temp()
+	w.g.line("%v = %v i64 %v, %v", moved, operation, pointer, steps)
+	var checked string = w.temp()
+	w.g.line("%v = call i64 @at(i64 %v)", checked, moved)
+	w.g.line("store i64 %v, ptr %%p", checked)
+}
+
+// constant returns value truncated to cell width, written signed as LLVM prints it.
+func (w *llvmWriter) constant(value uint64) string {
+	value &= memory.CellMax(w.width)
+	switch w.width {
+	case memory.CellWidth8:
+		return fmt.Sprintf("%d", int8(value))
+	case memory.CellWidth16:
+		return fmt.Sprintf("%d", int16(value))
+	}
+	return fmt.Sprintf("%d", int32(value))
+}
+
+// llvmString returns text as an LLVM string constant, escaping quotes, backslashes and non printable bytes.
+func llvmString(text string) string {
+	var ret strings.Builder
+	ret.WriteByte('"')
+	for _, char := range []byte(text) {
+		if char < ' ' || char > '~' || char == '"' || char == '\\' {
+			fmt.Fprintf(&ret, "\\%02X", char)
+		} else {
+			ret.WriteByte(char)
+		}
+	}
+	ret.WriteByte('"')
+	return ret.String()
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	"github.com/Anslen/Bfck/internal/testprograms"
+)
+
+func TestLLVMGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetLLVM)
+}
+
+// TestLLVMRun interprets every module with lli and compares it with CodeRunner.
+func TestLLVMRun(t *testing.T) {
+	lli, err := exec.LookPath("lli")
+	if err != nil {
+		t.Skip("no lli found")
+	}
+
+	// Generated IR uses opaque pointers, which are default since LLVM 15
+	version, err := exec.Command(lli, "--version").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var match []string = regexp.MustCompile(`LLVM version (\d+)`).FindStringSubmatch(string(version))
+	if match == nil {
+		t.Skipf("unknown lli version: %s", version)
+	}
+	var flags []string
+	switch major, _ := strconv.Atoi(match[1]); {
+	case major < 14:
+		t.Skipf("lli %v does not support opaque pointers", major)
+
+	case major == 14:
+		flags = append(flags, "-opaque-pointers")
+	}
+
+	var dir string = t.TempDir()
+	for _, each := range testprograms.Load(t) {
+		var module string = filepath.Join(dir, each.Name+".ll")
+		if err = os.WriteFile(module, compile(t, each, codecompiler.TargetLLVM), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		checkRun(t, each, exec.Command(lli, append(flags, module)...))
+	}
+}
@@ -0,0 +1,364 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+)
+
+// Return values of the exported run function of WebAssembly target.
+const (
+	WasmFinish   = iota // Program finished
+	WasmLeftEdge        // Pointer moved left of address 0
+	WasmTapeEnd         // Pointer moved beyond tape end
+)
+
+// WebAssembly opcodes and types used by compileWasm.
+const (
+	wasmBlock    = 0x02
+	wasmLoop     = 0x03
+	wasmIf       = 0x04
+	wasmEnd      = 0x0B
+	wasmBr       = 0x0C
+	wasmBrIf     = 0x0D
+	wasmReturn   = 0x0F
+	wasmCall     = 0x10
+	wasmSelect   = 0x1B
+	wasmLocalGet = 0x20
+	wasmLocalSet = 0x21
+	wasmLocalTee = 0x22
+	wasmLoad32   = 0x28 // i32.load
+	wasmLoad8    = 0x2D // i32.load8_u
+	wasmLoad16   = 0x2F // i32.load16_u
+	wasmStore32  = 0x36 // i32.store
+	wasmStore8   = 0x3A // i32.store8
+	wasmStore16  = 0x3B // i32.store16
+	wasmConst    = 0x41 // i32.const
+	wasmEqz      = 0x45
+	wasmLessS    = 0x48
+	wasmGreaterS = 0x4E // i32.ge_s
+	wasmAdd      = 0x6A
+	wasmSub      = 0x6B
+	wasmMul      = 0x6C
+	wasmEmpty    = 0x40 // Block type without result
+	wasmI32      = 0x7F
+)
+
+// Indexes of imported functions and locals of run.
+const (
+	wasmInput  = 0 // Imported bfck.input, returns next byte or -1 at end of input
+	wasmOutput = 1 // Imported bfck.output, writes the byte given
+	wasmRun    = 2
+
+	wasmPointer = 0 // Byte address of the current cell
+	wasmTemp    = 1
+)
+
+// wasmMaxTape is the largest tape in bytes, keeping byte addresses positive as i32.
+const wasmMaxTape = 1 << 31
+
+// compileWasm writes a binary WebAssembly module exporting memory and run, which returns
+// WasmFinish, WasmLeftEdge or WasmTapeEnd.
+//
+// Cells are stored little endian from address 0 of linear memory. Input and output are imported
+// from module "bfck" as input() -> i32 and output(i32).
+func compileWasm(c *code.Code, options Options, g *generator) (err error) {
+	var cellBytes int = options.CellWidth / 8
+	var tapeBytes int64 = int64(options.TapeSize) * int64(cellBytes)
+	if tapeBytes > wasmMaxTape {
+		return fmt.Errorf("Error: tape of %v bytes is too large for WebAssembly", tapeBytes)
+	}
+
+	var w *wasmWriter = &wasmWriter{cellBytes: cellBytes, tapeBytes: tapeBytes}
+	w.code(c, options.EOF)
+
+	// Module header
+	g.output.Write([]byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00})
+
+	// Custom section noting the source like other targets do
+	var note *bytes.Buffer = new(bytes.Buffer)
+	wasmName(note, "bfck")
+	note.WriteString(fmt.Sprintf("Generated by bfck from %v: %v cells of %v bits, %v", options.Source, options.TapeSize, options.CellWidth, eofComment(options.EOF)))
+	wasmSection(g.output, 0, note.Bytes())
+
+	// Types: () -> i32 for input and run, (i32) -> () for output
+	wasmSection(g.output, 1, []byte{2, 0x60, 0, 1, wasmI32, 0x60, 1, wasmI32, 0})
+
+	// Imports
+	var imports *bytes.Buffer = new(bytes.Buffer)
+	imports.WriteByte(2)
+	wasmName(imports, "bfck")
+	wasmName(imports, "input")
+	imports.Write([]byte{0x00, 0})
+	wasmName(imports, "bfck")
+	wasmName(imports, "output")
+	imports.Write([]byte{0x00, 1})
+	wasmSection(g.output, 2, imports.Bytes())
+
+	// Function run of type 0
+	wasmSection(g.output, 3, []byte{1, 0})
+
+	// Memory in pages of 64KiB, at least one
+	var memorySection []byte = []byte{1, 0x00}
+	memorySection = binary.AppendUvarint(memorySection, uint64(max((tapeBytes+0xFFFF)>>16, 1)))
+	wasmSection(g.output, 5, memorySection)
+
+	// Exports
+	var exports *bytes.Buffer = new(bytes.Buffer)
+	exports.WriteByte(2)
+	wasmName(exports, "memory")
+	exports.Write([]byte{0x02, 0})
+	wasmName(exports, "run")
+	exports.Write([]byte{0x00, wasmRun})
+	wasmSection(g.output, 7, exports.Bytes())
+
+	// Code of run with two i32 locals
+	var body []byte = append([]byte{1, 2, wasmI32}, w.body.Bytes()...)
+	var codeSection []byte = binary.AppendUvarint([]byte{1}, uint64(len(body)))
+	wasmSection(g.output, 10, append(codeSection, body...))
+	return
+}
+
+// wasmSection writes a section with its id and size.
+func wasmSection(output *bytes.Buffer, id byte, content []byte) {
+	output.WriteByte(id)
+	output.Write(binary.AppendUvarint(nil, uint64(len(content))))
+	output.Write(content)
+}
+
+// wasmName writes a name as a length prefixed UTF-8 string.
+func wasmName(output *bytes.Buffer, name string) {
+	output.Write(binary.AppendUvarint(nil, uint64(len(name))))
+	output.WriteString(name)
+}
+
+// wasmWriter writes the instructions of run.
+type wasmWriter struct {
+	body      bytes.Buffer
+	cellBytes int
+	tapeBytes int64
+}
+
+// code writes instructions for all operators, followed by returning WasmFinish.
+//
+// Loops become a block around a loop, so br_if 1 leaves and br 0 tests again.
+func (w *wasmWriter) code(c *code.Code, eof coderunner.EOFPolicy) {
+	walk(c, func(index int, loop int) {
+		var auxiliary uint64 = c.Auxiliary[index]
+		var offset int = c.Offsets[index]
+
+		switch c.Operators[index] {
+		case code.OpAdd:
+			w.update(offset, func() { w.constant(int64(auxiliary)); w.emit(wasmAdd) })
+
+		case code.OpSub:
+			w.update(offset, func() { w.constant(int64(auxiliary)); w.emit(wasmSub) })
+
+		case code.OpMoveRight:
+			w.move(int64(auxiliary))
+
+		case code.OpMoveLeft:
+			w.move(-int64(auxiliary))
+
+		case code.OpLeftBracket:
+			w.emit(wasmBlock, wasmEmpty, wasmLoop, wasmEmpty)
+			w.load(0)
+			w.emit(wasmEqz, wasmBrIf, 1)
+
+		case code.OpRightBracket:
+			w.emit(wasmBr, 0, wasmEnd, wasmEnd)
+
+		case code.OpInput:
+			w.input(eof)
+
+		case code.OpOutput:
+			// Lowest byte of the cell
+			w.emit(wasmLocalGet, wasmPointer, wasmLoad8, 0, 0)
+			w.emit(wasmCall, wasmOutput)
+
+		case code.OpClear:
+			w.store(offset, func() { w.constant(0) })
+
+		case code.OpSet:
+			w.store(offset, func() { w.constant(int64(auxiliary)) })
+
+		case code.OpScan:
+			w.emit(wasmBlock, wasmEmpty, wasmLoop, wasmEmpty)
+			w.load(0)
+			w.emit(wasmEqz, wasmBrIf, 1)
+			w.move(int64(auxiliary))
+			w.emit(wasmBr, 0, wasmEnd, wasmEnd)
+
+		case code.OpMultiply:
+			// Add cell times factor to each target, wrapping like the cell does
+			w.load(0)
+			w.emit(wasmIf, wasmEmpty)
+			for _, target := range c.MulTargets[auxiliary] {
+				w.update(target.Offset, func() {
+					w.load(0)
+					w.constant(target.Factor)
+					w.emit(wasmMul, wasmAdd)
+				})
+			}
+			w.store(0, func() { w.constant(0) })
+			w.emit(wasmEnd)
+		}
+	})
+	w.constant(WasmFinish)
+	w.emit(wasmEnd)
+}
+
+// move moves pointer by steps cells, returning from run if it leaves the tape.
+func (w *wasmWriter) move(steps int64) {
+	w.emit(wasmLocalGet, wasmPointer)
+	w.constant(steps * int64(w.cellBytes))
+	w.emit(wasmAdd, wasmLocalSet, wasmPointer)
+	if steps > 0 {
+		w.checkEnd(0)
+	} else {
+		w.checkLeft(0)
+	}
+}
+
+// checkLeft returns WasmLeftEdge if the cell at offset is left of address 0.
+func (w *wasmWriter) checkLeft(offset int) {
+	w.address(offset)
+	w.constant(0)
+	w.emit(wasmLessS, wasmIf, wasmEmpty)
+	w.constant(WasmLeftEdge)
+	w.emit(wasmReturn, wasmEnd)
+}
+
+// checkEnd returns WasmTapeEnd if the cell at offset is beyond tape end.
+func (w *wasmWriter) checkEnd(offset int) {
+	w.address(offset)
+	w.constant(w.tapeBytes)
+	w.emit(wasmGreaterS, wasmIf, wasmEmpty)
+	w.constant(WasmTapeEnd)
+	w.emit(wasmReturn, wasmEnd)
+}
+
+// check returns from run if the cell at offset is outside the tape, the current cell always is inside.
+func (w *wasmWriter) check(offset int) {
+	if offset > 0 {
+		w.checkEnd(offset)
+	} else if offset < 0 {
+		w.checkLeft(offset)
+	}
+}
+
+// address pushes the byte address of the cell at offset.
+func (w *wasmWriter) address(offset int) {
+	w.emit(wasmLocalGet, wasmPointer)
+	if offset != 0 {
+		w.constant(int64(offset * w.cellBytes))
+		w.emit(wasmAdd)
+	}
+}
+
+// load pushes the cell at offset, which must be inside the tape.
+func (w *wasmWriter) load(offset int) {
+	w.address(offset)
+	// Memory argument is log2 of alignment and offset 0
+	switch w.cellBytes {
+	case 1:
+		w.emit(wasmLoad8, 0, 0)
+	case 2:
+		w.emit(wasmLoad16, 1, 0)
+	case 4:
+		w.emit(wasmLoad32, 2, 0)
+	}
+}
+
+// store sets the cell at offset to the value pushed by value, checking tape first.
+func (w *wasmWriter) store(offset int, value func()) {
+	w.check(offset)
+	w.address(offset)
+	value()
+	// Narrow stores truncate to cell width
+	switch w.cellBytes {
+	case 1:
+		w.emit(wasmStore8, 0, 0)
+	case 2:
+		w.emit(wasmStore16, 1, 0)
+	case 4:
+		w.emit(wasmStore32, 2, 0)
+	}
+}
+
+// update sets the cell at offset to the result of change, which gets the cell value on stack.
+func (w *wasmWriter) update(offset int, change func()) {
+	w.store(offset, func() {
+		w.load(offset)
+		change()
+	})
+}
+
+// input calls imported input and stores the byte in current cell, applying EOF policy at end of input.
+func (w *wasmWriter) input(eof coderunner.EOFPolicy) {
+	switch eof {
+	case coderunner.EOFZero:
+		// select keeps input if it is not negative, otherwise 0
+		w.store(0, func() {
+			w.emit(wasmCall, wasmInput, wasmLocalTee, wasmTemp)
+			w.constant(0)
+			w.emit(wasmLocalGet, wasmTemp)
+			w.constant(0)
+			w.emit(wasmGreaterS, wasmSelect)
+		})
+
+	case coderunner.EOFMinusOne:
+		// -1 is truncated to all ones of cell width
+		w.store(0, func() { w.emit(wasmCall, wasmInput) })
+
+	case coderunner.EOFUnchanged:
+		w.emit(wasmCall, wasmInput, wasmLocalSet, wasmTemp)
+		w.emit(wasmLocalGet, wasmTemp)
+		w.constant(0)
+		w.emit(wasmGreaterS, wasmIf, wasmEmpty)
+		w.store(0, func() { w.emit(wasmLocalGet, wasmTemp) })
+		w.emit(wasmEnd)
+	}
+}
+
+// constant pushes value truncated to i32.
+func (w *wasmWriter) constant(value int64) {
+	w.emit(wasmConst)
+	w.body.Write(wasmSigned(int64(int32(value))))
+}
+
+func (w *wasmWriter) emit(bytes ...byte) {
+	w.body.Write(bytes)
+}
+
+// wasmSigned encodes value as signed LEB128.
+func wasmSigned(value int64) (ret []byte) {
+	for {
+		var part byte = byte(value & 0x7F)
+		value >>= 7
+		if (value == 0 && part&0x40 == 0) || (value == -1 && part&0x40 != 0) {
+			return append(ret, part)
+		}
+		ret = append(ret, part|0x80)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package codecompiler_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
+	"github.com/Anslen/Bfck/internal/testprograms"
+)
+
+// wasmHost runs the module given as argument with stdin as input, exiting with 1 unless run returns 0.
+const wasmHost string = `const fs = require("fs");
+const input = fs.readFileSync(0);
+const output = [];
+let position = 0;
+WebAssembly.instantiate(fs.readFileSync(process.argv[2]), {
+  bfck: {
+    input: () => (position < input.length ? input[position++] : -1),
+    output: (value) => output.push(value & 0xff),
+  },
+}).then(({ instance }) => {
+  const status = instance.exports.run();
+  process.stdout.write(Buffer.from(output));
+  process.exitCode = status === 0 ? 0 : 1;
+});
+`
+
+func TestWasmGolden(t *testing.T) {
+	checkGolden(t, codecompiler.TargetWasm)
+}
+
+// TestWasmRun runs every module on Node.js and compares it with CodeRunner.
+func TestWasmRun(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("no node found")
+	}
+
+	var dir string = t.TempDir()
+	var host string = filepath.Join(dir, "host.js")
+	if err = os.WriteFile(host, []byte(wasmHost), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, each := range testprograms.Load(t) {
+		var module string = filepath.Join(dir, each.Name+".wasm")
+		if err = os.WriteFile(module, compile(t, each, codecompiler.TargetWasm), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		checkRun(t, each, exec.Command(node, host, module))
+	}
+}
@@ -0,0 +1,94 @@
+; Generated by bfck from echo.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build on Linux x86-64: nasm -f elf64 echo.asm -o echo.o && ld echo.o -o echo
+; rbx: tape address, r12: pointer
+
+    default rel
+TAPE_SIZE equ 30000
+
+    section .bss
+tape:
+    resb TAPE_SIZE * 1
+io_byte:
+    resb 1
+
+    section .rodata
+left_message:
+    db "Error: pointer moved left of address 0 (address -1)", 10
+left_message_length equ $ - left_message
+end_message:
+    db "Error: pointer moved beyond tape end (address 30000, tape size 30000)", 10
+end_message_length equ $ - end_message
+
+    section .text
+    global _start
+_start:
+    lea rbx, [tape]
+    xor r12d, r12d
+
+    ; line 1
+    ; 0: Input 1
+    call read_byte
+    xor ecx, ecx
+    test eax, eax
+    cmovs eax, ecx
+    mov byte [rbx + r12], al
+L1:
+    ; 1: LeftBracket 5
+    cmp byte [rbx + r12], 0
+    je L1_End
+    ; 2: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 3: Input 1
+    call read_byte
+    xor ecx, ecx
+    test eax, eax
+    cmovs eax, ecx
+    mov byte [rbx + r12], al
+    ; 4: RightBracket 2
+    jmp L1
+L1_End:
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+; read_byte sets eax to the next byte of stdin, or -1 at end of input.
+read_byte:
+    xor eax, eax
+    xor edi, edi
+    lea rsi, [io_byte]
+    mov edx, 1
+    syscall
+    cmp rax, 1
+    jne read_eof
+    movzx eax, byte [io_byte]
+    ret
+read_eof:
+    mov eax, -1
+    ret
+
+; write_byte writes al to stdout.
+write_byte:
+    mov byte [io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
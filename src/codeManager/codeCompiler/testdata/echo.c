@@ -0,0 +1,42 @@
+/* Generated by bfck from echo.bf: 30000 cells of 8 bits, ',' stores 0 at end of input */
+
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+
+#define TAPE_SIZE 30000
+
+typedef uint8_t cell;
+
+static cell tape[TAPE_SIZE];
+
+/* at returns the tape index, stopping the program if it is outside the tape. */
+static inline long at(long index) {
+    if (index < 0) {
+        fprintf(stderr, "Error: pointer moved left of address 0 (address -1)\n");
+        exit(1);
+    }
+    if (index >= TAPE_SIZE) {
+        fprintf(stderr, "Error: pointer moved beyond tape end (address %d, tape size %d)\n", TAPE_SIZE, TAPE_SIZE);
+        exit(1);
+    }
+    return index;
+}
+
+int main(void) {
+    long p = 0;
+    int input;
+
+    /* line 1 */
+    fflush(stdout);
+    input = getchar();
+    tape[p] = input == EOF ? 0 : (cell)input;
+    while (tape[p]) { /* L1 */
+        putchar(tape[p]);
+        fflush(stdout);
+        input = getchar();
+        tape[p] = input == EOF ? 0 : (cell)input;
+    } /* L1 End */
+
+    return 0;
+}
@@ -0,0 +1,84 @@
+// Code generated by bfck from echo.bf. DO NOT EDIT.
+
+// Package echo runs echo.bf with 30000 cells of 8 bits, ',' stores 0 at end of input.
+package echo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const tapeSize = 30000
+
+type cell = uint8
+
+var (
+	ErrLeftEdge = errors.New("pointer moved left of address 0")
+	ErrTapeEnd  = errors.New("pointer moved beyond tape end")
+)
+
+// tapeError is raised by at and returned by Run.
+type tapeError struct {
+	err error
+}
+
+// at returns the tape index, raising tapeError if it is outside the tape.
+func at(index int) int {
+	if index < 0 {
+		panic(tapeError{fmt.Errorf("%w (address -1)", ErrLeftEdge)})
+	}
+	if index >= tapeSize {
+		panic(tapeError{fmt.Errorf("%w (address %v, tape size %v)", ErrTapeEnd, tapeSize, tapeSize)})
+	}
+	return index
+}
+
+// read returns the next input byte as a cell, current is the cell value before reading.
+func read(reader *bufio.Reader, current cell) (cell, error) {
+	value, err := reader.ReadByte()
+	if err == io.EOF {
+		return 0, nil
+	}
+	return cell(value), err
+}
+
+// Run runs the program, reading ',' from in and writing '.' to out.
+func Run(in io.Reader, out io.Writer) (err error) {
+	var tape []cell = make([]cell, tapeSize)
+	var p int = 0
+	var reader *bufio.Reader = bufio.NewReader(in)
+	var writer *bufio.Writer = bufio.NewWriter(out)
+	defer func() {
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+		if recovered := recover(); recovered != nil {
+			if tapeErr, isTapeError := recovered.(tapeError); isTapeError {
+				err = tapeErr.err
+			} else {
+				panic(recovered)
+			}
+		}
+	}()
+
+	// line 1
+	if err = writer.Flush(); err != nil {
+		return
+	}
+	if tape[p], err = read(reader, tape[p]); err != nil {
+		return
+	}
+	for tape[p] != 0 { // L1
+		writer.WriteByte(byte(tape[p]))
+		if err = writer.Flush(); err != nil {
+			return
+		}
+		if tape[p], err = read(reader, tape[p]); err != nil {
+			return
+		}
+	} // L1 End
+
+	return nil
+}
@@ -0,0 +1,85 @@
+; Generated by bfck from echo.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build: clang -O2 echo.ll -o echo
+source_filename = "echo.bf"
+
+@tape = internal global [30000 x i8] zeroinitializer
+@left_message = private constant [52 x i8] c"Error: pointer moved left of address 0 (address -1)\0A"
+@end_message = private constant [70 x i8] c"Error: pointer moved beyond tape end (address 30000, tape size 30000)\0A"
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i64 @write(i32, ptr, i64)
+declare void @exit(i32) noreturn
+
+; at returns the tape index, stopping the program if it is outside the tape.
+define internal i64 @at(i64 %index) {
+entry:
+  %left = icmp slt i64 %index, 0
+  br i1 %left, label %left_edge, label %check_end
+check_end:
+  %end = icmp sge i64 %index, 30000
+  br i1 %end, label %tape_end, label %inside
+inside:
+  ret i64 %index
+left_edge:
+  call void @fail(ptr @left_message, i64 52)
+  unreachable
+tape_end:
+  call void @fail(ptr @end_message, i64 70)
+  unreachable
+}
+
+; fail writes message to stderr and exits with status 1, flushing output.
+define internal void @fail(ptr %message, i64 %length) noreturn {
+entry:
+  %written = call i64 @write(i32 2, ptr %message, i64 %length)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+
+  ; line 1
+  ; 0: Input 1
+  call i32 @fflush(ptr null)
+  %t1 = call i32 @getchar()
+  %t2 = icmp eq i32 %t1, -1
+  %t3 = load i64, ptr %p
+  %t4 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t3
+  %t5 = trunc i32 %t1 to i8
+  %t6 = select i1 %t2, i8 0, i8 %t5
+  store i8 %t6, ptr %t4
+  ; 1: LeftBracket 5
+  br label %L1
+L1:
+  %t7 = load i64, ptr %p
+  %t8 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t7
+  %t9 = load i8, ptr %t8
+  %t10 = icmp ne i8 %t9, 0
+  br i1 %t10, label %L1.body, label %L1.end
+L1.body:
+  ; 2: Output 1
+  %t11 = load i64, ptr %p
+  %t12 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t11
+  %t13 = load i8, ptr %t12
+  %t14 = zext i8 %t13 to i32
+  call i32 @putchar(i32 %t14)
+  ; 3: Input 1
+  call i32 @fflush(ptr null)
+  %t15 = call i32 @getchar()
+  %t16 = icmp eq i32 %t15, -1
+  %t17 = load i64, ptr %p
+  %t18 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t17
+  %t19 = trunc i32 %t15 to i8
+  %t20 = select i1 %t16, i8 0, i8 %t19
+  store i8 %t20, ptr %t18
+  ; 4: RightBracket 2
+  br label %L1
+L1.end:
+
+  ret i32 0
+}
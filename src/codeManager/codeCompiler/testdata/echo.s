@@ -0,0 +1,94 @@
+# Generated by bfck from echo.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+# Build on Linux x86-64: as echo.s -o echo.o && ld echo.o -o echo
+# rbx: tape address, r12: pointer
+
+    .intel_syntax noprefix
+    .equ TAPE_SIZE, 30000
+
+    .bss
+tape:
+    .zero TAPE_SIZE * 1
+io_byte:
+    .zero 1
+
+    .section .rodata
+left_message:
+    .ascii "Error: pointer moved left of address 0 (address -1)\n"
+    .equ left_message_length, . - left_message
+end_message:
+    .ascii "Error: pointer moved beyond tape end (address 30000, tape size 30000)\n"
+    .equ end_message_length, . - end_message
+
+    .text
+    .globl _start
+_start:
+    lea rbx, [rip + tape]
+    xor r12d, r12d
+
+    # line 1
+    # 0: Input 1
+    call read_byte
+    xor ecx, ecx
+    test eax, eax
+    cmovs eax, ecx
+    mov byte ptr [rbx + r12], al
+L1:
+    # 1: LeftBracket 5
+    cmp byte ptr [rbx + r12], 0
+    je L1_End
+    # 2: Output 1
+    movzx eax, byte ptr [rbx + r12]
+    call write_byte
+    # 3: Input 1
+    call read_byte
+    xor ecx, ecx
+    test eax, eax
+    cmovs eax, ecx
+    mov byte ptr [rbx + r12], al
+    # 4: RightBracket 2
+    jmp L1
+L1_End:
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+# read_byte sets eax to the next byte of stdin, or -1 at end of input.
+read_byte:
+    xor eax, eax
+    xor edi, edi
+    lea rsi, [rip + io_byte]
+    mov edx, 1
+    syscall
+    cmp rax, 1
+    jne read_eof
+    movzx eax, byte ptr [rip + io_byte]
+    ret
+read_eof:
+    mov eax, -1
+    ret
+
+# write_byte writes al to stdout.
+write_byte:
+    mov byte ptr [rip + io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [rip + io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [rip + left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [rip + end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
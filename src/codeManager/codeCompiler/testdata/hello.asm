@@ -0,0 +1,236 @@
+; Generated by bfck from hello.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build on Linux x86-64: nasm -f elf64 hello.asm -o hello.o && ld hello.o -o hello
+; rbx: tape address, r12: pointer
+
+    default rel
+TAPE_SIZE equ 30000
+
+    section .bss
+tape:
+    resb TAPE_SIZE * 1
+io_byte:
+    resb 1
+
+    section .rodata
+left_message:
+    db "Error: pointer moved left of address 0 (address -1)", 10
+left_message_length equ $ - left_message
+end_message:
+    db "Error: pointer moved beyond tape end (address 30000, tape size 30000)", 10
+end_message_length equ $ - end_message
+
+    section .text
+    global _start
+_start:
+    lea rbx, [tape]
+    xor r12d, r12d
+
+    ; line 1
+    ; 0: Add 8
+    add byte [rbx + r12], 8
+L1:
+    ; 1: LeftBracket 21
+    cmp byte [rbx + r12], 0
+    je L1_End
+    ; 2: Add 4 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 4
+    ; 3: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+L2:
+    ; 4: Multiply 0 [+1]+=2 [+2]+=3 [+3]+=3 [+4]+=1
+    movzx eax, byte [rbx + r12]
+    test eax, eax
+    jz L2_End
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 2
+    add byte [rbx + r12 + 1], cl
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 3
+    add byte [rbx + r12 + 2], cl
+    lea rdx, [r12 + 3]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 3
+    add byte [rbx + r12 + 3], cl
+    lea rdx, [r12 + 4]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 4], al
+    mov byte [rbx + r12], 0
+L2_End:
+    ; 12: Add 1 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 1
+    ; 13: Add 1 [+2]
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 2], 1
+    ; 14: Sub 1 [+3]
+    lea rdx, [r12 + 3]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    sub byte [rbx + r12 + 3], 1
+    ; 15: Add 1 [+5]
+    lea rdx, [r12 + 5]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 5], 1
+    ; 16: MoveRight 5
+    add r12, 5
+    cmp r12, TAPE_SIZE
+    jge tape_end
+scan17:
+    ; 17: Scan -1
+    cmp byte [rbx + r12], 0
+    je scan17_End
+    sub r12, 1
+    jl left_edge
+    jmp scan17
+scan17_End:
+    ; 18: Sub 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    sub byte [rbx + r12 - 1], 1
+    ; 19: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 20: RightBracket 2
+    jmp L1
+L1_End:
+    ; 21: MoveRight 2
+    add r12, 2
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 22: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 23: Sub 3 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    sub byte [rbx + r12 + 1], 3
+    ; 24: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 25: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 26: Add 7
+    add byte [rbx + r12], 7
+    ; 27: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 28: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 29: Add 3
+    add byte [rbx + r12], 3
+    ; 30: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 31: MoveRight 2
+    add r12, 2
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 32: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 33: Sub 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    sub byte [rbx + r12 - 1], 1
+    ; 34: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 35: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 36: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 37: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 38: Add 3
+    add byte [rbx + r12], 3
+    ; 39: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 40: Sub 6
+    sub byte [rbx + r12], 6
+    ; 41: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 42: Sub 8
+    sub byte [rbx + r12], 8
+    ; 43: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 44: Add 1 [+2]
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 2], 1
+    ; 45: MoveRight 2
+    add r12, 2
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 46: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+    ; 47: Add 2 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 2
+    ; 48: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 49: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+; write_byte writes al to stdout.
+write_byte:
+    mov byte [io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
@@ -0,0 +1,81 @@
+/* Generated by bfck from hello.bf: 30000 cells of 8 bits, ',' stores 0 at end of input */
+
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+
+#define TAPE_SIZE 30000
+
+typedef uint8_t cell;
+
+static cell tape[TAPE_SIZE];
+
+/* at returns the tape index, stopping the program if it is outside the tape. */
+static inline long at(long index) {
+    if (index < 0) {
+        fprintf(stderr, "Error: pointer moved left of address 0 (address -1)\n");
+        exit(1);
+    }
+    if (index >= TAPE_SIZE) {
+        fprintf(stderr, "Error: pointer moved beyond tape end (address %d, tape size %d)\n", TAPE_SIZE, TAPE_SIZE);
+        exit(1);
+    }
+    return index;
+}
+
+int main(void) {
+    long p = 0;
+
+    /* line 1 */
+    tape[p] += 8;
+    while (tape[p]) { /* L1 */
+        tape[at(p + 1)] += 4;
+        p = at(p + 1);
+        if (tape[p]) { /* L2, multiply */
+            tape[at(p + 1)] += tape[p] * 2u;
+            tape[at(p + 2)] += tape[p] * 3u;
+            tape[at(p + 3)] += tape[p] * 3u;
+            tape[at(p + 4)] += tape[p];
+            tape[p] = 0;
+        } /* L2 End */
+        tape[at(p + 1)] += 1;
+        tape[at(p + 2)] += 1;
+        tape[at(p + 3)] -= 1;
+        tape[at(p + 5)] += 1;
+        p = at(p + 5);
+        while (tape[p]) p = at(p - 1);
+        tape[at(p - 1)] -= 1;
+        p = at(p - 1);
+    } /* L1 End */
+    p = at(p + 2);
+    putchar(tape[p]);
+    tape[at(p + 1)] -= 3;
+    p = at(p + 1);
+    putchar(tape[p]);
+    tape[p] += 7;
+    putchar(tape[p]);
+    putchar(tape[p]);
+    tape[p] += 3;
+    putchar(tape[p]);
+    p = at(p + 2);
+    putchar(tape[p]);
+    tape[at(p - 1)] -= 1;
+    p = at(p - 1);
+    putchar(tape[p]);
+    p = at(p - 1);
+    putchar(tape[p]);
+    tape[p] += 3;
+    putchar(tape[p]);
+    tape[p] -= 6;
+    putchar(tape[p]);
+    tape[p] -= 8;
+    putchar(tape[p]);
+    tape[at(p + 2)] += 1;
+    p = at(p + 2);
+    putchar(tape[p]);
+    tape[at(p + 1)] += 2;
+    p = at(p + 1);
+    putchar(tape[p]);
+
+    return 0;
+}
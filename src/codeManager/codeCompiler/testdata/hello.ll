@@ -0,0 +1,365 @@
+; Generated by bfck from hello.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build: clang -O2 hello.ll -o hello
+source_filename = "hello.bf"
+
+@tape = internal global [30000 x i8] zeroinitializer
+@left_message = private constant [52 x i8] c"Error: pointer moved left of address 0 (address -1)\0A"
+@end_message = private constant [70 x i8] c"Error: pointer moved beyond tape end (address 30000, tape size 30000)\0A"
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i64 @write(i32, ptr, i64)
+declare void @exit(i32) noreturn
+
+; at returns the tape index, stopping the program if it is outside the tape.
+define internal i64 @at(i64 %index) {
+entry:
+  %left = icmp slt i64 %index, 0
+  br i1 %left, label %left_edge, label %check_end
+check_end:
+  %end = icmp sge i64 %index, 30000
+  br i1 %end, label %tape_end, label %inside
+inside:
+  ret i64 %index
+left_edge:
+  call void @fail(ptr @left_message, i64 52)
+  unreachable
+tape_end:
+  call void @fail(ptr @end_message, i64 70)
+  unreachable
+}
+
+; fail writes message to stderr and exits with status 1, flushing output.
+define internal void @fail(ptr %message, i64 %length) noreturn {
+entry:
+  %written = call i64 @write(i32 2, ptr %message, i64 %length)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+
+  ; line 1
+  ; 0: Add 8
+  %t1 = load i64, ptr %p
+  %t2 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t1
+  %t3 = load i8, ptr %t2
+  %t4 = add i8 %t3, 8
+  store i8 %t4, ptr %t2
+  ; 1: LeftBracket 21
+  br label %L1
+L1:
+  %t5 = load i64, ptr %p
+  %t6 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t5
+  %t7 = load i8, ptr %t6
+  %t8 = icmp ne i8 %t7, 0
+  br i1 %t8, label %L1.body, label %L1.end
+L1.body:
+  ; 2: Add 4 [+1]
+  %t9 = load i64, ptr %p
+  %t10 = add i64 %t9, 1
+  %t11 = call i64 @at(i64 %t10)
+  %t12 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t11
+  %t13 = load i8, ptr %t12
+  %t14 = add i8 %t13, 4
+  store i8 %t14, ptr %t12
+  ; 3: MoveRight 1
+  %t15 = load i64, ptr %p
+  %t16 = add i64 %t15, 1
+  %t17 = call i64 @at(i64 %t16)
+  store i64 %t17, ptr %p
+  ; 4: Multiply 0 [+1]+=2 [+2]+=3 [+3]+=3 [+4]+=1
+  br label %L2
+L2:
+  %t18 = load i64, ptr %p
+  %t19 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t18
+  %t20 = load i8, ptr %t19
+  %t21 = icmp ne i8 %t20, 0
+  br i1 %t21, label %L2.body, label %L2.end
+L2.body:
+  %t22 = mul i8 %t20, 2
+  %t23 = load i64, ptr %p
+  %t24 = add i64 %t23, 1
+  %t25 = call i64 @at(i64 %t24)
+  %t26 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t25
+  %t27 = load i8, ptr %t26
+  %t28 = add i8 %t27, %t22
+  store i8 %t28, ptr %t26
+  %t29 = mul i8 %t20, 3
+  %t30 = load i64, ptr %p
+  %t31 = add i64 %t30, 2
+  %t32 = call i64 @at(i64 %t31)
+  %t33 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t32
+  %t34 = load i8, ptr %t33
+  %t35 = add i8 %t34, %t29
+  store i8 %t35, ptr %t33
+  %t36 = mul i8 %t20, 3
+  %t37 = load i64, ptr %p
+  %t38 = add i64 %t37, 3
+  %t39 = call i64 @at(i64 %t38)
+  %t40 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t39
+  %t41 = load i8, ptr %t40
+  %t42 = add i8 %t41, %t36
+  store i8 %t42, ptr %t40
+  %t43 = load i64, ptr %p
+  %t44 = add i64 %t43, 4
+  %t45 = call i64 @at(i64 %t44)
+  %t46 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t45
+  %t47 = load i8, ptr %t46
+  %t48 = add i8 %t47, %t20
+  store i8 %t48, ptr %t46
+  %t49 = load i64, ptr %p
+  %t50 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t49
+  store i8 0, ptr %t50
+  br label %L2.end
+L2.end:
+  ; 12: Add 1 [+1]
+  %t51 = load i64, ptr %p
+  %t52 = add i64 %t51, 1
+  %t53 = call i64 @at(i64 %t52)
+  %t54 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t53
+  %t55 = load i8, ptr %t54
+  %t56 = add i8 %t55, 1
+  store i8 %t56, ptr %t54
+  ; 13: Add 1 [+2]
+  %t57 = load i64, ptr %p
+  %t58 = add i64 %t57, 2
+  %t59 = call i64 @at(i64 %t58)
+  %t60 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t59
+  %t61 = load i8, ptr %t60
+  %t62 = add i8 %t61, 1
+  store i8 %t62, ptr %t60
+  ; 14: Sub 1 [+3]
+  %t63 = load i64, ptr %p
+  %t64 = add i64 %t63, 3
+  %t65 = call i64 @at(i64 %t64)
+  %t66 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t65
+  %t67 = load i8, ptr %t66
+  %t68 = sub i8 %t67, 1
+  store i8 %t68, ptr %t66
+  ; 15: Add 1 [+5]
+  %t69 = load i64, ptr %p
+  %t70 = add i64 %t69, 5
+  %t71 = call i64 @at(i64 %t70)
+  %t72 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t71
+  %t73 = load i8, ptr %t72
+  %t74 = add i8 %t73, 1
+  store i8 %t74, ptr %t72
+  ; 16: MoveRight 5
+  %t75 = load i64, ptr %p
+  %t76 = add i64 %t75, 5
+  %t77 = call i64 @at(i64 %t76)
+  store i64 %t77, ptr %p
+  ; 17: Scan -1
+  br label %scan17
+scan17:
+  %t78 = load i64, ptr %p
+  %t79 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t78
+  %t80 = load i8, ptr %t79
+  %t81 = icmp ne i8 %t80, 0
+  br i1 %t81, label %scan17.body, label %scan17.end
+scan17.body:
+  %t82 = load i64, ptr %p
+  %t83 = sub i64 %t82, 1
+  %t84 = call i64 @at(i64 %t83)
+  store i64 %t84, ptr %p
+  br label %scan17
+scan17.end:
+  ; 18: Sub 1 [-1]
+  %t85 = load i64, ptr %p
+  %t86 = sub i64 %t85, 1
+  %t87 = call i64 @at(i64 %t86)
+  %t88 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t87
+  %t89 = load i8, ptr %t88
+  %t90 = sub i8 %t89, 1
+  store i8 %t90, ptr %t88
+  ; 19: MoveLeft 1
+  %t91 = load i64, ptr %p
+  %t92 = sub i64 %t91, 1
+  %t93 = call i64 @at(i64 %t92)
+  store i64 %t93, ptr %p
+  ; 20: RightBracket 2
+  br label %L1
+L1.end:
+  ; 21: MoveRight 2
+  %t94 = load i64, ptr %p
+  %t95 = add i64 %t94, 2
+  %t96 = call i64 @at(i64 %t95)
+  store i64 %t96, ptr %p
+  ; 22: Output 1
+  %t97 = load i64, ptr %p
+  %t98 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t97
+  %t99 = load i8, ptr %t98
+  %t100 = zext i8 %t99 to i32
+  call i32 @putchar(i32 %t100)
+  ; 23: Sub 3 [+1]
+  %t101 = load i64, ptr %p
+  %t102 = add i64 %t101, 1
+  %t103 = call i64 @at(i64 %t102)
+  %t104 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t103
+  %t105 = load i8, ptr %t104
+  %t106 = sub i8 %t105, 3
+  store i8 %t106, ptr %t104
+  ; 24: MoveRight 1
+  %t107 = load i64, ptr %p
+  %t108 = add i64 %t107, 1
+  %t109 = call i64 @at(i64 %t108)
+  store i64 %t109, ptr %p
+  ; 25: Output 1
+  %t110 = load i64, ptr %p
+  %t111 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t110
+  %t112 = load i8, ptr %t111
+  %t113 = zext i8 %t112 to i32
+  call i32 @putchar(i32 %t113)
+  ; 26: Add 7
+  %t114 = load i64, ptr %p
+  %t115 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t114
+  %t116 = load i8, ptr %t115
+  %t117 = add i8 %t116, 7
+  store i8 %t117, ptr %t115
+  ; 27: Output 1
+  %t118 = load i64, ptr %p
+  %t119 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t118
+  %t120 = load i8, ptr %t119
+  %t121 = zext i8 %t120 to i32
+  call i32 @putchar(i32 %t121)
+  ; 28: Output 1
+  %t122 = load i64, ptr %p
+  %t123 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t122
+  %t124 = load i8, ptr %t123
+  %t125 = zext i8 %t124 to i32
+  call i32 @putchar(i32 %t125)
+  ; 29: Add 3
+  %t126 = load i64, ptr %p
+  %t127 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t126
+  %t128 = load i8, ptr %t127
+  %t129 = add i8 %t128, 3
+  store i8 %t129, ptr %t127
+  ; 30: Output 1
+  %t130 = load i64, ptr %p
+  %t131 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t130
+  %t132 = load i8, ptr %t131
+  %t133 = zext i8 %t132 to i32
+  call i32 @putchar(i32 %t133)
+  ; 31: MoveRight 2
+  %t134 = load i64, ptr %p
+  %t135 = add i64 %t134, 2
+  %t136 = call i64 @at(i64 %t135)
+  store i64 %t136, ptr %p
+  ; 32: Output 1
+  %t137 = load i64, ptr %p
+  %t138 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t137
+  %t139 = load i8, ptr %t138
+  %t140 = zext i8 %t139 to i32
+  call i32 @putchar(i32 %t140)
+  ; 33: Sub 1 [-1]
+  %t141 = load i64, ptr %p
+  %t142 = sub i64 %t141, 1
+  %t143 = call i64 @at(i64 %t142)
+  %t144 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t143
+  %t145 = load i8, ptr %t144
+  %t146 = sub i8 %t145, 1
+  store i8 %t146, ptr %t144
+  ; 34: MoveLeft 1
+  %t147 = load i64, ptr %p
+  %t148 = sub i64 %t147, 1
+  %t149 = call i64 @at(i64 %t148)
+  store i64 %t149, ptr %p
+  ; 35: Output 1
+  %t150 = load i64, ptr %p
+  %t151 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t150
+  %t152 = load i8, ptr %t151
+  %t153 = zext i8 %t152 to i32
+  call i32 @putchar(i32 %t153)
+  ; 36: MoveLeft 1
+  %t154 = load i64, ptr %p
+  %t155 = sub i64 %t154, 1
+  %t156 = call i64 @at(i64 %t155)
+  store i64 %t156, ptr %p
+  ; 37: Output 1
+  %t157 = load i64, ptr %p
+  %t158 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t157
+  %t159 = load i8, ptr %t158
+  %t160 = zext i8 %t159 to i32
+  call i32 @putchar(i32 %t160)
+  ; 38: Add 3
+  %t161 = load i64, ptr %p
+  %t162 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t161
+  %t163 = load i8, ptr %t162
+  %t164 = add i8 %t163, 3
+  store i8 %t164, ptr %t162
+  ; 39: Output 1
+  %t165 = load i64, ptr %p
+  %t166 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t165
+  %t167 = load i8, ptr %t166
+  %t168 = zext i8 %t167 to i32
+  call i32 @putchar(i32 %t168)
+  ; 40: Sub 6
+  %t169 = load i64, ptr %p
+  %t170 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t169
+  %t171 = load i8, ptr %t170
+  %t172 = sub i8 %t171, 6
+  store i8 %t172, ptr %t170
+  ; 41: Output 1
+  %t173 = load i64, ptr %p
+  %t174 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t173
+  %t175 = load i8, ptr %t174
+  %t176 = zext i8 %t175 to i32
+  call i32 @putchar(i32 %t176)
+  ; 42: Sub 8
+  %t177 = load i64, ptr %p
+  %t178 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t177
+  %t179 = load i8, ptr %t178
+  %t180 = sub i8 %t179, 8
+  store i8 %t180, ptr %t178
+  ; 43: Output 1
+  %t181 = load i64, ptr %p
+  %t182 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t181
+  %t183 = load i8, ptr %t182
+  %t184 = zext i8 %t183 to i32
+  call i32 @putchar(i32 %t184)
+  ; 44: Add 1 [+2]
+  %t185 = load i64, ptr %p
+  %t186 = add i64 %t185, 2
+  %t187 = call i64 @at(i64 %t186)
+  %t188 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t187
+  %t189 = load i8, ptr %t188
+  %t190 = add i8 %t189, 1
+  store i8 %t190, ptr %t188
+  ; 45: MoveRight 2
+  %t191 = load i64, ptr %p
+  %t192 = add i64 %t191, 2
+  %t193 = call i64 @at(i64 %t192)
+  store i64 %t193, ptr %p
+  ; 46: Output 1
+  %t194 = load i64, ptr %p
+  %t195 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t194
+  %t196 = load i8, ptr %t195
+  %t197 = zext i8 %t196 to i32
+  call i32 @putchar(i32 %t197)
+  ; 47: Add 2 [+1]
+  %t198 = load i64, ptr %p
+  %t199 = add i64 %t198, 1
+  %t200 = call i64 @at(i64 %t199)
+  %t201 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t200
+  %t202 = load i8, ptr %t201
+  %t203 = add i8 %t202, 2
+  store i8 %t203, ptr %t201
+  ; 48: MoveRight 1
+  %t204 = load i64, ptr %p
+  %t205 = add i64 %t204, 1
+  %t206 = call i64 @at(i64 %t205)
+  store i64 %t206, ptr %p
+  ; 49: Output 1
+  %t207 = load i64, ptr %p
+  %t208 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t207
+  %t209 = load i8, ptr %t208
+  %t210 = zext i8 %t209 to i32
+  call i32 @putchar(i32 %t210)
+
+  ret i32 0
+}
@@ -0,0 +1,202 @@
+; Generated by bfck from multiply.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build on Linux x86-64: nasm -f elf64 multiply.asm -o multiply.o && ld multiply.o -o multiply
+; rbx: tape address, r12: pointer
+
+    default rel
+TAPE_SIZE equ 30000
+
+    section .bss
+tape:
+    resb TAPE_SIZE * 1
+io_byte:
+    resb 1
+
+    section .rodata
+left_message:
+    db "Error: pointer moved left of address 0 (address -1)", 10
+left_message_length equ $ - left_message
+end_message:
+    db "Error: pointer moved beyond tape end (address 30000, tape size 30000)", 10
+end_message_length equ $ - end_message
+
+    section .text
+    global _start
+_start:
+    lea rbx, [tape]
+    xor r12d, r12d
+
+    ; line 2
+    ; 0: Add 5
+    add byte [rbx + r12], 5
+L1:
+    ; 1: LeftBracket 12
+    cmp byte [rbx + r12], 0
+    je L1_End
+    ; 2: Add 5 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 5
+    ; 3: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+L2:
+    ; 4: Multiply 0 [+1]+=3
+    movzx eax, byte [rbx + r12]
+    test eax, eax
+    jz L2_End
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 3
+    add byte [rbx + r12 + 1], cl
+    mov byte [rbx + r12], 0
+L2_End:
+    ; 9: Sub 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    sub byte [rbx + r12 - 1], 1
+    ; 10: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 11: RightBracket 2
+    jmp L1
+L1_End:
+    ; 12: MoveRight 2
+    add r12, 2
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 13: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    ; line 3
+    ; 14: Add 3
+    add byte [rbx + r12], 3
+L3:
+    ; 15: LeftBracket 32
+    cmp byte [rbx + r12], 0
+    je L3_End
+    ; 16: Add 2 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 2
+    ; 17: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+L4:
+    ; 18: LeftBracket 29
+    cmp byte [rbx + r12], 0
+    je L4_End
+    ; 19: Add 3 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 3
+    ; 20: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+L5:
+    ; 21: Multiply 1 [+1]+=4
+    movzx eax, byte [rbx + r12]
+    test eax, eax
+    jz L5_End
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 4
+    add byte [rbx + r12 + 1], cl
+    mov byte [rbx + r12], 0
+L5_End:
+    ; 26: Sub 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    sub byte [rbx + r12 - 1], 1
+    ; 27: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 28: RightBracket 19
+    jmp L4
+L4_End:
+    ; 29: Sub 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    sub byte [rbx + r12 - 1], 1
+    ; 30: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 31: RightBracket 16
+    jmp L3
+L3_End:
+    ; 32: MoveRight 3
+    add r12, 3
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 33: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    ; line 4
+    ; 34: Add 6 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 6
+    ; 35: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+L6:
+    ; 36: Multiply 2 [-1]-=2
+    movzx eax, byte [rbx + r12]
+    test eax, eax
+    jz L6_End
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    imul ecx, eax, 2
+    sub byte [rbx + r12 - 1], cl
+    mov byte [rbx + r12], 0
+L6_End:
+    ; 41: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 42: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+; write_byte writes al to stdout.
+write_byte:
+    mov byte [io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
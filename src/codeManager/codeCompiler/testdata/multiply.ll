@@ -0,0 +1,291 @@
+; Generated by bfck from multiply.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build: clang -O2 multiply.ll -o multiply
+source_filename = "multiply.bf"
+
+@tape = internal global [30000 x i8] zeroinitializer
+@left_message = private constant [52 x i8] c"Error: pointer moved left of address 0 (address -1)\0A"
+@end_message = private constant [70 x i8] c"Error: pointer moved beyond tape end (address 30000, tape size 30000)\0A"
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i64 @write(i32, ptr, i64)
+declare void @exit(i32) noreturn
+
+; at returns the tape index, stopping the program if it is outside the tape.
+define internal i64 @at(i64 %index) {
+entry:
+  %left = icmp slt i64 %index, 0
+  br i1 %left, label %left_edge, label %check_end
+check_end:
+  %end = icmp sge i64 %index, 30000
+  br i1 %end, label %tape_end, label %inside
+inside:
+  ret i64 %index
+left_edge:
+  call void @fail(ptr @left_message, i64 52)
+  unreachable
+tape_end:
+  call void @fail(ptr @end_message, i64 70)
+  unreachable
+}
+
+; fail writes message to stderr and exits with status 1, flushing output.
+define internal void @fail(ptr %message, i64 %length) noreturn {
+entry:
+  %written = call i64 @write(i32 2, ptr %message, i64 %length)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+
+  ; line 2
+  ; 0: Add 5
+  %t1 = load i64, ptr %p
+  %t2 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t1
+  %t3 = load i8, ptr %t2
+  %t4 = add i8 %t3, 5
+  store i8 %t4, ptr %t2
+  ; 1: LeftBracket 12
+  br label %L1
+L1:
+  %t5 = load i64, ptr %p
+  %t6 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t5
+  %t7 = load i8, ptr %t6
+  %t8 = icmp ne i8 %t7, 0
+  br i1 %t8, label %L1.body, label %L1.end
+L1.body:
+  ; 2: Add 5 [+1]
+  %t9 = load i64, ptr %p
+  %t10 = add i64 %t9, 1
+  %t11 = call i64 @at(i64 %t10)
+  %t12 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t11
+  %t13 = load i8, ptr %t12
+  %t14 = add i8 %t13, 5
+  store i8 %t14, ptr %t12
+  ; 3: MoveRight 1
+  %t15 = load i64, ptr %p
+  %t16 = add i64 %t15, 1
+  %t17 = call i64 @at(i64 %t16)
+  store i64 %t17, ptr %p
+  ; 4: Multiply 0 [+1]+=3
+  br label %L2
+L2:
+  %t18 = load i64, ptr %p
+  %t19 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t18
+  %t20 = load i8, ptr %t19
+  %t21 = icmp ne i8 %t20, 0
+  br i1 %t21, label %L2.body, label %L2.end
+L2.body:
+  %t22 = mul i8 %t20, 3
+  %t23 = load i64, ptr %p
+  %t24 = add i64 %t23, 1
+  %t25 = call i64 @at(i64 %t24)
+  %t26 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t25
+  %t27 = load i8, ptr %t26
+  %t28 = add i8 %t27, %t22
+  store i8 %t28, ptr %t26
+  %t29 = load i64, ptr %p
+  %t30 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t29
+  store i8 0, ptr %t30
+  br label %L2.end
+L2.end:
+  ; 9: Sub 1 [-1]
+  %t31 = load i64, ptr %p
+  %t32 = sub i64 %t31, 1
+  %t33 = call i64 @at(i64 %t32)
+  %t34 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t33
+  %t35 = load i8, ptr %t34
+  %t36 = sub i8 %t35, 1
+  store i8 %t36, ptr %t34
+  ; 10: MoveLeft 1
+  %t37 = load i64, ptr %p
+  %t38 = sub i64 %t37, 1
+  %t39 = call i64 @at(i64 %t38)
+  store i64 %t39, ptr %p
+  ; 11: RightBracket 2
+  br label %L1
+L1.end:
+  ; 12: MoveRight 2
+  %t40 = load i64, ptr %p
+  %t41 = add i64 %t40, 2
+  %t42 = call i64 @at(i64 %t41)
+  store i64 %t42, ptr %p
+  ; 13: Output 1
+  %t43 = load i64, ptr %p
+  %t44 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t43
+  %t45 = load i8, ptr %t44
+  %t46 = zext i8 %t45 to i32
+  call i32 @putchar(i32 %t46)
+
+  ; line 3
+  ; 14: Add 3
+  %t47 = load i64, ptr %p
+  %t48 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t47
+  %t49 = load i8, ptr %t48
+  %t50 = add i8 %t49, 3
+  store i8 %t50, ptr %t48
+  ; 15: LeftBracket 32
+  br label %L3
+L3:
+  %t51 = load i64, ptr %p
+  %t52 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t51
+  %t53 = load i8, ptr %t52
+  %t54 = icmp ne i8 %t53, 0
+  br i1 %t54, label %L3.body, label %L3.end
+L3.body:
+  ; 16: Add 2 [+1]
+  %t55 = load i64, ptr %p
+  %t56 = add i64 %t55, 1
+  %t57 = call i64 @at(i64 %t56)
+  %t58 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t57
+  %t59 = load i8, ptr %t58
+  %t60 = add i8 %t59, 2
+  store i8 %t60, ptr %t58
+  ; 17: MoveRight 1
+  %t61 = load i64, ptr %p
+  %t62 = add i64 %t61, 1
+  %t63 = call i64 @at(i64 %t62)
+  store i64 %t63, ptr %p
+  ; 18: LeftBracket 29
+  br label %L4
+L4:
+  %t64 = load i64, ptr %p
+  %t65 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t64
+  %t66 = load i8, ptr %t65
+  %t67 = icmp ne i8 %t66, 0
+  br i1 %t67, label %L4.body, label %L4.end
+L4.body:
+  ; 19: Add 3 [+1]
+  %t68 = load i64, ptr %p
+  %t69 = add i64 %t68, 1
+  %t70 = call i64 @at(i64 %t69)
+  %t71 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t70
+  %t72 = load i8, ptr %t71
+  %t73 = add i8 %t72, 3
+  store i8 %t73, ptr %t71
+  ; 20: MoveRight 1
+  %t74 = load i64, ptr %p
+  %t75 = add i64 %t74, 1
+  %t76 = call i64 @at(i64 %t75)
+  store i64 %t76, ptr %p
+  ; 21: Multiply 1 [+1]+=4
+  br label %L5
+L5:
+  %t77 = load i64, ptr %p
+  %t78 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t77
+  %t79 = load i8, ptr %t78
+  %t80 = icmp ne i8 %t79, 0
+  br i1 %t80, label %L5.body, label %L5.end
+L5.body:
+  %t81 = mul i8 %t79, 4
+  %t82 = load i64, ptr %p
+  %t83 = add i64 %t82, 1
+  %t84 = call i64 @at(i64 %t83)
+  %t85 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t84
+  %t86 = load i8, ptr %t85
+  %t87 = add i8 %t86, %t81
+  store i8 %t87, ptr %t85
+  %t88 = load i64, ptr %p
+  %t89 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t88
+  store i8 0, ptr %t89
+  br label %L5.end
+L5.end:
+  ; 26: Sub 1 [-1]
+  %t90 = load i64, ptr %p
+  %t91 = sub i64 %t90, 1
+  %t92 = call i64 @at(i64 %t91)
+  %t93 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t92
+  %t94 = load i8, ptr %t93
+  %t95 = sub i8 %t94, 1
+  store i8 %t95, ptr %t93
+  ; 27: MoveLeft 1
+  %t96 = load i64, ptr %p
+  %t97 = sub i64 %t96, 1
+  %t98 = call i64 @at(i64 %t97)
+  store i64 %t98, ptr %p
+  ; 28: RightBracket 19
+  br label %L4
+L4.end:
+  ; 29: Sub 1 [-1]
+  %t99 = load i64, ptr %p
+  %t100 = sub i64 %t99, 1
+  %t101 = call i64 @at(i64 %t100)
+  %t102 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t101
+  %t103 = load i8, ptr %t102
+  %t104 = sub i8 %t103, 1
+  store i8 %t104, ptr %t102
+  ; 30: MoveLeft 1
+  %t105 = load i64, ptr %p
+  %t106 = sub i64 %t105, 1
+  %t107 = call i64 @at(i64 %t106)
+  store i64 %t107, ptr %p
+  ; 31: RightBracket 16
+  br label %L3
+L3.end:
+  ; 32: MoveRight 3
+  %t108 = load i64, ptr %p
+  %t109 = add i64 %t108, 3
+  %t110 = call i64 @at(i64 %t109)
+  store i64 %t110, ptr %p
+  ; 33: Output 1
+  %t111 = load i64, ptr %p
+  %t112 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t111
+  %t113 = load i8, ptr %t112
+  %t114 = zext i8 %t113 to i32
+  call i32 @putchar(i32 %t114)
+
+  ; line 4
+  ; 34: Add 6 [+1]
+  %t115 = load i64, ptr %p
+  %t116 = add i64 %t115, 1
+  %t117 = call i64 @at(i64 %t116)
+  %t118 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t117
+  %t119 = load i8, ptr %t118
+  %t120 = add i8 %t119, 6
+  store i8 %t120, ptr %t118
+  ; 35: MoveRight 1
+  %t121 = load i64, ptr %p
+  %t122 = add i64 %t121, 1
+  %t123 = call i64 @at(i64 %t122)
+  store i64 %t123, ptr %p
+  ; 36: Multiply 2 [-1]-=2
+  br label %L6
+L6:
+  %t124 = load i64, ptr %p
+  %t125 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t124
+  %t126 = load i8, ptr %t125
+  %t127 = icmp ne i8 %t126, 0
+  br i1 %t127, label %L6.body, label %L6.end
+L6.body:
+  %t128 = mul i8 %t126, 2
+  %t129 = load i64, ptr %p
+  %t130 = sub i64 %t129, 1
+  %t131 = call i64 @at(i64 %t130)
+  %t132 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t131
+  %t133 = load i8, ptr %t132
+  %t134 = sub i8 %t133, %t128
+  store i8 %t134, ptr %t132
+  %t135 = load i64, ptr %p
+  %t136 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t135
+  store i8 0, ptr %t136
+  br label %L6.end
+L6.end:
+  ; 41: MoveLeft 1
+  %t137 = load i64, ptr %p
+  %t138 = sub i64 %t137, 1
+  %t139 = call i64 @at(i64 %t138)
+  store i64 %t139, ptr %p
+  ; 42: Output 1
+  %t140 = load i64, ptr %p
+  %t141 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t140
+  %t142 = load i8, ptr %t141
+  %t143 = zext i8 %t142 to i32
+  call i32 @putchar(i32 %t143)
+
+  ret i32 0
+}
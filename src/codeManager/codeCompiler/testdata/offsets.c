@@ -0,0 +1,52 @@
+/* Generated by bfck from offsets.bf: 30000 cells of 8 bits, ',' stores 0 at end of input */
+
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+
+#define TAPE_SIZE 30000
+
+typedef uint8_t cell;
+
+static cell tape[TAPE_SIZE];
+
+/* at returns the tape index, stopping the program if it is outside the tape. */
+static inline long at(long index) {
+    if (index < 0) {
+        fprintf(stderr, "Error: pointer moved left of address 0 (address -1)\n");
+        exit(1);
+    }
+    if (index >= TAPE_SIZE) {
+        fprintf(stderr, "Error: pointer moved beyond tape end (address %d, tape size %d)\n", TAPE_SIZE, TAPE_SIZE);
+        exit(1);
+    }
+    return index;
+}
+
+int main(void) {
+    long p = 0;
+
+    /* line 2 */
+    tape[at(p + 1)] += 3;
+    if (tape[p]) { /* L1, multiply */
+        tape[at(p + 2)] += tape[p];
+        tape[p] = 0;
+    } /* L1 End */
+    tape[at(p + 3)] += 4;
+    tape[p] += 3;
+    if (tape[p]) { /* L2, multiply */
+        tape[at(p + 1)] += tape[p];
+        tape[at(p + 2)] += tape[p] * 2u;
+        tape[p] = 0;
+    } /* L2 End */
+    p = at(p + 1);
+    putchar(tape[p]);
+    p = at(p + 1);
+    putchar(tape[p]);
+    p = at(p + 1);
+    putchar(tape[p]);
+    p = at(p + 1);
+    putchar(tape[p]);
+
+    return 0;
+}
@@ -0,0 +1,79 @@
+// Code generated by bfck from offsets.bf. DO NOT EDIT.
+
+// Package offsets runs offsets.bf with 30000 cells of 8 bits, ',' stores 0 at end of input.
+package offsets
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const tapeSize = 30000
+
+type cell = uint8
+
+var (
+	ErrLeftEdge = errors.New("pointer moved left of address 0")
+	ErrTapeEnd  = errors.New("pointer moved beyond tape end")
+)
+
+// tapeError is raised by at and returned by Run.
+type tapeError struct {
+	err error
+}
+
+// at returns the tape index, raising tapeError if it is outside the tape.
+func at(index int) int {
+	if index < 0 {
+		panic(tapeError{fmt.Errorf("%w (address -1)", ErrLeftEdge)})
+	}
+	if index >= tapeSize {
+		panic(tapeError{fmt.Errorf("%w (address %v, tape size %v)", ErrTapeEnd, tapeSize, tapeSize)})
+	}
+	return index
+}
+
+// Run runs the program, reading ',' from in and writing '.' to out.
+func Run(in io.Reader, out io.Writer) (err error) {
+	var tape []cell = make([]cell, tapeSize)
+	var p int = 0
+	var writer *bufio.Writer = bufio.NewWriter(out)
+	defer func() {
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+		if recovered := recover(); recovered != nil {
+			if tapeErr, isTapeError := recovered.(tapeError); isTapeError {
+				err = tapeErr.err
+			} else {
+				panic(recovered)
+			}
+		}
+	}()
+
+	// line 2
+	tape[at(p+1)] += 3
+	if tape[p] != 0 { // L1, multiply
+		tape[at(p+2)] += tape[p]
+		tape[p] = 0
+	} // L1 End
+	tape[at(p+3)] += 4
+	tape[p] += 3
+	if tape[p] != 0 { // L2, multiply
+		tape[at(p+1)] += tape[p]
+		tape[at(p+2)] += tape[p] * 2
+		tape[p] = 0
+	} // L2 End
+	p = at(p + 1)
+	writer.WriteByte(byte(tape[p]))
+	p = at(p + 1)
+	writer.WriteByte(byte(tape[p]))
+	p = at(p + 1)
+	writer.WriteByte(byte(tape[p]))
+	p = at(p + 1)
+	writer.WriteByte(byte(tape[p]))
+
+	return nil
+}
@@ -0,0 +1,165 @@
+; Generated by bfck from offsets.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build: clang -O2 offsets.ll -o offsets
+source_filename = "offsets.bf"
+
+@tape = internal global [30000 x i8] zeroinitializer
+@left_message = private constant [52 x i8] c"Error: pointer moved left of address 0 (address -1)\0A"
+@end_message = private constant [70 x i8] c"Error: pointer moved beyond tape end (address 30000, tape size 30000)\0A"
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i64 @write(i32, ptr, i64)
+declare void @exit(i32) noreturn
+
+; at returns the tape index, stopping the program if it is outside the tape.
+define internal i64 @at(i64 %index) {
+entry:
+  %left = icmp slt i64 %index, 0
+  br i1 %left, label %left_edge, label %check_end
+check_end:
+  %end = icmp sge i64 %index, 30000
+  br i1 %end, label %tape_end, label %inside
+inside:
+  ret i64 %index
+left_edge:
+  call void @fail(ptr @left_message, i64 52)
+  unreachable
+tape_end:
+  call void @fail(ptr @end_message, i64 70)
+  unreachable
+}
+
+; fail writes message to stderr and exits with status 1, flushing output.
+define internal void @fail(ptr %message, i64 %length) noreturn {
+entry:
+  %written = call i64 @write(i32 2, ptr %message, i64 %length)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+
+  ; line 2
+  ; 0: Add 3 [+1]
+  %t1 = load i64, ptr %p
+  %t2 = add i64 %t1, 1
+  %t3 = call i64 @at(i64 %t2)
+  %t4 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t3
+  %t5 = load i8, ptr %t4
+  %t6 = add i8 %t5, 3
+  store i8 %t6, ptr %t4
+  ; 1: Multiply 0 [+2]+=1
+  br label %L1
+L1:
+  %t7 = load i64, ptr %p
+  %t8 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t7
+  %t9 = load i8, ptr %t8
+  %t10 = icmp ne i8 %t9, 0
+  br i1 %t10, label %L1.body, label %L1.end
+L1.body:
+  %t11 = load i64, ptr %p
+  %t12 = add i64 %t11, 2
+  %t13 = call i64 @at(i64 %t12)
+  %t14 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t13
+  %t15 = load i8, ptr %t14
+  %t16 = add i8 %t15, %t9
+  store i8 %t16, ptr %t14
+  %t17 = load i64, ptr %p
+  %t18 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t17
+  store i8 0, ptr %t18
+  br label %L1.end
+L1.end:
+  ; 6: Add 4 [+3]
+  %t19 = load i64, ptr %p
+  %t20 = add i64 %t19, 3
+  %t21 = call i64 @at(i64 %t20)
+  %t22 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t21
+  %t23 = load i8, ptr %t22
+  %t24 = add i8 %t23, 4
+  store i8 %t24, ptr %t22
+  ; 7: Add 3
+  %t25 = load i64, ptr %p
+  %t26 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t25
+  %t27 = load i8, ptr %t26
+  %t28 = add i8 %t27, 3
+  store i8 %t28, ptr %t26
+  ; 8: Multiply 1 [+1]+=1 [+2]+=2
+  br label %L2
+L2:
+  %t29 = load i64, ptr %p
+  %t30 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t29
+  %t31 = load i8, ptr %t30
+  %t32 = icmp ne i8 %t31, 0
+  br i1 %t32, label %L2.body, label %L2.end
+L2.body:
+  %t33 = load i64, ptr %p
+  %t34 = add i64 %t33, 1
+  %t35 = call i64 @at(i64 %t34)
+  %t36 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t35
+  %t37 = load i8, ptr %t36
+  %t38 = add i8 %t37, %t31
+  store i8 %t38, ptr %t36
+  %t39 = mul i8 %t31, 2
+  %t40 = load i64, ptr %p
+  %t41 = add i64 %t40, 2
+  %t42 = call i64 @at(i64 %t41)
+  %t43 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t42
+  %t44 = load i8, ptr %t43
+  %t45 = add i8 %t44, %t39
+  store i8 %t45, ptr %t43
+  %t46 = load i64, ptr %p
+  %t47 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t46
+  store i8 0, ptr %t47
+  br label %L2.end
+L2.end:
+  ; 14: MoveRight 1
+  %t48 = load i64, ptr %p
+  %t49 = add i64 %t48, 1
+  %t50 = call i64 @at(i64 %t49)
+  store i64 %t50, ptr %p
+  ; 15: Output 1
+  %t51 = load i64, ptr %p
+  %t52 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t51
+  %t53 = load i8, ptr %t52
+  %t54 = zext i8 %t53 to i32
+  call i32 @putchar(i32 %t54)
+  ; 16: MoveRight 1
+  %t55 = load i64, ptr %p
+  %t56 = add i64 %t55, 1
+  %t57 = call i64 @at(i64 %t56)
+  store i64 %t57, ptr %p
+  ; 17: Output 1
+  %t58 = load i64, ptr %p
+  %t59 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t58
+  %t60 = load i8, ptr %t59
+  %t61 = zext i8 %t60 to i32
+  call i32 @putchar(i32 %t61)
+  ; 18: MoveRight 1
+  %t62 = load i64, ptr %p
+  %t63 = add i64 %t62, 1
+  %t64 = call i64 @at(i64 %t63)
+  store i64 %t64, ptr %p
+  ; 19: Output 1
+  %t65 = load i64, ptr %p
+  %t66 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t65
+  %t67 = load i8, ptr %t66
+  %t68 = zext i8 %t67 to i32
+  call i32 @putchar(i32 %t68)
+  ; 20: MoveRight 1
+  %t69 = load i64, ptr %p
+  %t70 = add i64 %t69, 1
+  %t71 = call i64 @at(i64 %t70)
+  store i64 %t71, ptr %p
+  ; 21: Output 1
+  %t72 = load i64, ptr %p
+  %t73 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t72
+  %t74 = load i8, ptr %t73
+  %t75 = zext i8 %t74 to i32
+  call i32 @putchar(i32 %t75)
+
+  ret i32 0
+}
@@ -0,0 +1,124 @@
+# Generated by bfck from offsets.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+# Build on Linux x86-64: as offsets.s -o offsets.o && ld offsets.o -o offsets
+# rbx: tape address, r12: pointer
+
+    .intel_syntax noprefix
+    .equ TAPE_SIZE, 30000
+
+    .bss
+tape:
+    .zero TAPE_SIZE * 1
+io_byte:
+    .zero 1
+
+    .section .rodata
+left_message:
+    .ascii "Error: pointer moved left of address 0 (address -1)\n"
+    .equ left_message_length, . - left_message
+end_message:
+    .ascii "Error: pointer moved beyond tape end (address 30000, tape size 30000)\n"
+    .equ end_message_length, . - end_message
+
+    .text
+    .globl _start
+_start:
+    lea rbx, [rip + tape]
+    xor r12d, r12d
+
+    # line 2
+    # 0: Add 3 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte ptr [rbx + r12 + 1], 3
+L1:
+    # 1: Multiply 0 [+2]+=1
+    movzx eax, byte ptr [rbx + r12]
+    test eax, eax
+    jz L1_End
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte ptr [rbx + r12 + 2], al
+    mov byte ptr [rbx + r12], 0
+L1_End:
+    # 6: Add 4 [+3]
+    lea rdx, [r12 + 3]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte ptr [rbx + r12 + 3], 4
+    # 7: Add 3
+    add byte ptr [rbx + r12], 3
+L2:
+    # 8: Multiply 1 [+1]+=1 [+2]+=2
+    movzx eax, byte ptr [rbx + r12]
+    test eax, eax
+    jz L2_End
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte ptr [rbx + r12 + 1], al
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    imul ecx, eax, 2
+    add byte ptr [rbx + r12 + 2], cl
+    mov byte ptr [rbx + r12], 0
+L2_End:
+    # 14: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    # 15: Output 1
+    movzx eax, byte ptr [rbx + r12]
+    call write_byte
+    # 16: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    # 17: Output 1
+    movzx eax, byte ptr [rbx + r12]
+    call write_byte
+    # 18: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    # 19: Output 1
+    movzx eax, byte ptr [rbx + r12]
+    call write_byte
+    # 20: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    # 21: Output 1
+    movzx eax, byte ptr [rbx + r12]
+    call write_byte
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+# write_byte writes al to stdout.
+write_byte:
+    mov byte ptr [rip + io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [rip + io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [rip + left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [rip + end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
@@ -0,0 +1,167 @@
+; Generated by bfck from scan.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build on Linux x86-64: nasm -f elf64 scan.asm -o scan.o && ld scan.o -o scan
+; rbx: tape address, r12: pointer
+
+    default rel
+TAPE_SIZE equ 30000
+
+    section .bss
+tape:
+    resb TAPE_SIZE * 1
+io_byte:
+    resb 1
+
+    section .rodata
+left_message:
+    db "Error: pointer moved left of address 0 (address -1)", 10
+left_message_length equ $ - left_message
+end_message:
+    db "Error: pointer moved beyond tape end (address 30000, tape size 30000)", 10
+end_message_length equ $ - end_message
+
+    section .text
+    global _start
+_start:
+    lea rbx, [tape]
+    xor r12d, r12d
+
+    ; line 2
+    ; 0: Add 1
+    add byte [rbx + r12], 1
+    ; 1: Add 1 [+1]
+    lea rdx, [r12 + 1]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 1], 1
+    ; 2: Add 1 [+2]
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 2], 1
+    ; 3: Add 1 [+3]
+    lea rdx, [r12 + 3]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 3], 1
+    ; 4: Add 1 [+4]
+    lea rdx, [r12 + 4]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 4], 1
+scan5:
+    ; 5: Scan 1
+    cmp byte [rbx + r12], 0
+    je scan5_End
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    jmp scan5
+scan5_End:
+    ; 6: Add 8
+    add byte [rbx + r12], 8
+L1:
+    ; 7: Multiply 0 [-1]+=8
+    movzx eax, byte [rbx + r12]
+    test eax, eax
+    jz L1_End
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    imul ecx, eax, 8
+    add byte [rbx + r12 - 1], cl
+    mov byte [rbx + r12], 0
+L1_End:
+    ; 12: Add 1 [-1]
+    lea rdx, [r12 - 1]
+    test rdx, rdx
+    js left_edge
+    add byte [rbx + r12 - 1], 1
+    ; 13: MoveLeft 1
+    sub r12, 1
+    jl left_edge
+    ; 14: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    ; line 3
+scan15:
+    ; 15: Scan -1
+    cmp byte [rbx + r12], 0
+    je scan15_End
+    sub r12, 1
+    jl left_edge
+    jmp scan15
+scan15_End:
+    ; 16: MoveRight 1
+    add r12, 1
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    ; 17: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    ; line 4
+    ; 18: Add 1 [+8]
+    lea rdx, [r12 + 8]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 8], 1
+    ; 19: Add 1 [+6]
+    lea rdx, [r12 + 6]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 6], 1
+    ; 20: Add 1 [+4]
+    lea rdx, [r12 + 4]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 4], 1
+    ; 21: Add 1 [+2]
+    lea rdx, [r12 + 2]
+    cmp rdx, TAPE_SIZE
+    jge tape_end
+    add byte [rbx + r12 + 2], 1
+scan22:
+    ; 22: Scan 2
+    cmp byte [rbx + r12], 0
+    je scan22_End
+    add r12, 2
+    cmp r12, TAPE_SIZE
+    jge tape_end
+    jmp scan22
+scan22_End:
+    ; 23: MoveLeft 2
+    sub r12, 2
+    jl left_edge
+    ; 24: Output 1
+    movzx eax, byte [rbx + r12]
+    call write_byte
+
+    mov eax, 60
+    xor edi, edi
+    syscall
+
+; write_byte writes al to stdout.
+write_byte:
+    mov byte [io_byte], al
+    mov eax, 1
+    mov edi, 1
+    lea rsi, [io_byte]
+    mov edx, 1
+    syscall
+    ret
+
+left_edge:
+    lea rsi, [left_message]
+    mov edx, left_message_length
+    jmp fail
+tape_end:
+    lea rsi, [end_message]
+    mov edx, end_message_length
+fail:
+    mov eax, 1
+    mov edi, 2
+    syscall
+    mov eax, 60
+    mov edi, 1
+    syscall
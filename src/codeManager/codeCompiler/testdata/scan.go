@@ -0,0 +1,93 @@
+// Code generated by bfck from scan.bf. DO NOT EDIT.
+
+// Package scan runs scan.bf with 30000 cells of 8 bits, ',' stores 0 at end of input.
+package scan
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const tapeSize = 30000
+
+type cell = uint8
+
+var (
+	ErrLeftEdge = errors.New("pointer moved left of address 0")
+	ErrTapeEnd  = errors.New("pointer moved beyond tape end")
+)
+
+// tapeError is raised by at and returned by Run.
+type tapeError struct {
+	err error
+}
+
+// at returns the tape index, raising tapeError if it is outside the tape.
+func at(index int) int {
+	if index < 0 {
+		panic(tapeError{fmt.Errorf("%w (address -1)", ErrLeftEdge)})
+	}
+	if index >= tapeSize {
+		panic(tapeError{fmt.Errorf("%w (address %v, tape size %v)", ErrTapeEnd, tapeSize, tapeSize)})
+	}
+	return index
+}
+
+// Run runs the program, reading ',' from in and writing '.' to out.
+func Run(in io.Reader, out io.Writer) (err error) {
+	var tape []cell = make([]cell, tapeSize)
+	var p int = 0
+	var writer *bufio.Writer = bufio.NewWriter(out)
+	defer func() {
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+		if recovered := recover(); recovered != nil {
+			if tapeErr, isTapeError := recovered.(tapeError); isTapeError {
+				err = tapeErr.err
+			} else {
+				panic(recovered)
+			}
+		}
+	}()
+
+	// line 2
+	tape[p] += 1
+	tape[at(p+1)] += 1
+	tape[at(p+2)] += 1
+	tape[at(p+3)] += 1
+	tape[at(p+4)] += 1
+	for tape[p] != 0 {
+		p = at(p + 1)
+	}
+	tape[p] += 8
+	if tape[p] != 0 { // L1, multiply
+		tape[at(p-1)] += tape[p] * 8
+		tape[p] = 0
+	} // L1 End
+	tape[at(p-1)] += 1
+	p = at(p - 1)
+	writer.WriteByte(byte(tape[p]))
+
+	// line 3
+	for tape[p] != 0 {
+		p = at(p - 1)
+	}
+	p = at(p + 1)
+	writer.WriteByte(byte(tape[p]))
+
+	// line 4
+	tape[at(p+8)] += 1
+	tape[at(p+6)] += 1
+	tape[at(p+4)] += 1
+	tape[at(p+2)] += 1
+	for tape[p] != 0 {
+		p = at(p + 2)
+	}
+	p = at(p - 2)
+	writer.WriteByte(byte(tape[p]))
+
+	return nil
+}
@@ -0,0 +1,237 @@
+; Generated by bfck from scan.bf: 30000 cells of 8 bits, ',' stores 0 at end of input
+; Build: clang -O2 scan.ll -o scan
+source_filename = "scan.bf"
+
+@tape = internal global [30000 x i8] zeroinitializer
+@left_message = private constant [52 x i8] c"Error: pointer moved left of address 0 (address -1)\0A"
+@end_message = private constant [70 x i8] c"Error: pointer moved beyond tape end (address 30000, tape size 30000)\0A"
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i64 @write(i32, ptr, i64)
+declare void @exit(i32) noreturn
+
+; at returns the tape index, stopping the program if it is outside the tape.
+define internal i64 @at(i64 %index) {
+entry:
+  %left = icmp slt i64 %index, 0
+  br i1 %left, label %left_edge, label %check_end
+check_end:
+  %end = icmp sge i64 %index, 30000
+  br i1 %end, label %tape_end, label %inside
+inside:
+  ret i64 %index
+left_edge:
+  call void @fail(ptr @left_message, i64 52)
+  unreachable
+tape_end:
+  call void @fail(ptr @end_message, i64 70)
+  unreachable
+}
+
+; fail writes message to stderr and exits with status 1, flushing output.
+define internal void @fail(ptr %message, i64 %length) noreturn {
+entry:
+  %written = call i64 @write(i32 2, ptr %message, i64 %length)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+
+  ; line 2
+  ; 0: Add 1
+  %t1 = load i64, ptr %p
+  %t2 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t1
+  %t3 = load i8, ptr %t2
+  %t4 = add i8 %t3, 1
+  store i8 %t4, ptr %t2
+  ; 1: Add 1 [+1]
+  %t5 = load i64, ptr %p
+  %t6 = add i64 %t5, 1
+  %t7 = call i64 @at(i64 %t6)
+  %t8 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t7
+  %t9 = load i8, ptr %t8
+  %t10 = add i8 %t9, 1
+  store i8 %t10, ptr %t8
+  ; 2: Add 1 [+2]
+  %t11 = load i64, ptr %p
+  %t12 = add i64 %t11, 2
+  %t13 = call i64 @at(i64 %t12)
+  %t14 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t13
+  %t15 = load i8, ptr %t14
+  %t16 = add i8 %t15, 1
+  store i8 %t16, ptr %t14
+  ; 3: Add 1 [+3]
+  %t17 = load i64, ptr %p
+  %t18 = add i64 %t17, 3
+  %t19 = call i64 @at(i64 %t18)
+  %t20 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t19
+  %t21 = load i8, ptr %t20
+  %t22 = add i8 %t21, 1
+  store i8 %t22, ptr %t20
+  ; 4: Add 1 [+4]
+  %t23 = load i64, ptr %p
+  %t24 = add i64 %t23, 4
+  %t25 = call i64 @at(i64 %t24)
+  %t26 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t25
+  %t27 = load i8, ptr %t26
+  %t28 = add i8 %t27, 1
+  store i8 %t28, ptr %t26
+  ; 5: Scan 1
+  br label %scan5
+scan5:
+  %t29 = load i64, ptr %p
+  %t30 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t29
+  %t31 = load i8, ptr %t30
+  %t32 = icmp ne i8 %t31, 0
+  br i1 %t32, label %scan5.body, label %scan5.end
+scan5.body:
+  %t33 = load i64, ptr %p
+  %t34 = add i64 %t33, 1
+  %t35 = call i64 @at(i64 %t34)
+  store i64 %t35, ptr %p
+  br label %scan5
+scan5.end:
+  ; 6: Add 8
+  %t36 = load i64, ptr %p
+  %t37 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t36
+  %t38 = load i8, ptr %t37
+  %t39 = add i8 %t38, 8
+  store i8 %t39, ptr %t37
+  ; 7: Multiply 0 [-1]+=8
+  br label %L1
+L1:
+  %t40 = load i64, ptr %p
+  %t41 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t40
+  %t42 = load i8, ptr %t41
+  %t43 = icmp ne i8 %t42, 0
+  br i1 %t43, label %L1.body, label %L1.end
+L1.body:
+  %t44 = mul i8 %t42, 8
+  %t45 = load i64, ptr %p
+  %t46 = sub i64 %t45, 1
+  %t47 = call i64 @at(i64 %t46)
+  %t48 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t47
+  %t49 = load i8, ptr %t48
+  %t50 = add i8 %t49, %t44
+  store i8 %t50, ptr %t48
+  %t51 = load i64, ptr %p
+  %t52 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t51
+  store i8 0, ptr %t52
+  br label %L1.end
+L1.end:
+  ; 12: Add 1 [-1]
+  %t53 = load i64, ptr %p
+  %t54 = sub i64 %t53, 1
+  %t55 = call i64 @at(i64 %t54)
+  %t56 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t55
+  %t57 = load i8, ptr %t56
+  %t58 = add i8 %t57, 1
+  store i8 %t58, ptr %t56
+  ; 13: MoveLeft 1
+  %t59 = load i64, ptr %p
+  %t60 = sub i64 %t59, 1
+  %t61 = call i64 @at(i64 %t60)
+  store i64 %t61, ptr %p
+  ; 14: Output 1
+  %t62 = load i64, ptr %p
+  %t63 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t62
+  %t64 = load i8, ptr %t63
+  %t65 = zext i8 %t64 to i32
+  call i32 @putchar(i32 %t65)
+
+  ; line 3
+  ; 15: Scan -1
+  br label %scan15
+scan15:
+  %t66 = load i64, ptr %p
+  %t67 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t66
+  %t68 = load i8, ptr %t67
+  %t69 = icmp ne i8 %t68, 0
+  br i1 %t69, label %scan15.body, label %scan15.end
+scan15.body:
+  %t70 = load i64, ptr %p
+  %t71 = sub i64 %t70, 1
+  %t72 = call i64 @at(i64 %t71)
+  store i64 %t72, ptr %p
+  br label %scan15
+scan15.end:
+  ; 16: MoveRight 1
+  %t73 = load i64, ptr %p
+  %t74 = add i64 %t73, 1
+  %t75 = call i64 @at(i64 %t74)
+  store i64 %t75, ptr %p
+  ; 17: Output 1
+  %t76 = load i64, ptr %p
+  %t77 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t76
+  %t78 = load i8, ptr %t77
+  %t79 = zext i8 %t78 to i32
+  call i32 @putchar(i32 %t79)
+
+  ; line 4
+  ; 18: Add 1 [+8]
+  %t80 = load i64, ptr %p
+  %t81 = add i64 %t80, 8
+  %t82 = call i64 @at(i64 %t81)
+  %t83 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t82
+  %t84 = load i8, ptr %t83
+  %t85 = add i8 %t84, 1
+  store i8 %t85, ptr %t83
+  ; 19: Add 1 [+6]
+  %t86 = load i64, ptr %p
+  %t87 = add i64 %t86, 6
+  %t88 = call i64 @at(i64 %t87)
+  %t89 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t88
+  %t90 = load i8, ptr %t89
+  %t91 = add i8 %t90, 1
+  store i8 %t91, ptr %t89
+  ; 20: Add 1 [+4]
+  %t92 = load i64, ptr %p
+  %t93 = add i64 %t92, 4
+  %t94 = call i64 @at(i64 %t93)
+  %t95 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t94
+  %t96 = load i8, ptr %t95
+  %t97 = add i8 %t96, 1
+  store i8 %t97, ptr %t95
+  ; 21: Add 1 [+2]
+  %t98 = load i64, ptr %p
+  %t99 = add i64 %t98, 2
+  %t100 = call i64 @at(i64 %t99)
+  %t101 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t100
+  %t102 = load i8, ptr %t101
+  %t103 = add i8 %t102, 1
+  store i8 %t103, ptr %t101
+  ; 22: Scan 2
+  br label %scan22
+scan22:
+  %t104 = load i64, ptr %p
+  %t105 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t104
+  %t106 = load i8, ptr %t105
+  %t107 = icmp ne i8 %t106, 0
+  br i1 %t107, label %scan22.body, label %scan22.end
+scan22.body:
+  %t108 = load i64, ptr %p
+  %t109 = add i64 %t108, 2
+  %t110 = call i64 @at(i64 %t109)
+  store i64 %t110, ptr %p
+  br label %scan22
+scan22.end:
+  ; 23: MoveLeft 2
+  %t111 = load i64, ptr %p
+  %t112 = sub i64 %t111, 2
+  %t113 = call i64 @at(i64 %t112)
+  store i64 %t113, ptr %p
+  ; 24: Output 1
+  %t114 = load i64, ptr %p
+  %t115 = getelementptr inbounds [30000 x i8], ptr @tape, i64 0, i64 %t114
+  %t116 = load i8, ptr %t115
+  %t117 = zext i8 %t116 to i32
+  call i32 @putchar(i32 %t117)
+
+  ret i32 0
+}
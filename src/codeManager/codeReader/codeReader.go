@@ -18,15 +18,33 @@
 package codereader
 
 import (
+	"crypto/sha256"
 	"os"
 
+	"github.com/Anslen/Bfck/codeManager/bytecode"
 	"github.com/Anslen/Bfck/codeManager/code"
 	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
 	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
 )
 
-// Read reads the code from the given file path and returns a Code object.
-func Read(path string, debugFlag bool) (ret *coderunner.CodeRunner, err error) {
+// Read reads the code from the given file path, analyses it with the given optimisation level
+// and returns a CodeRunner with the given options.
+func Read(path string, debugFlag bool, level codeanalyser.OptLevel, options coderunner.Options) (ret *coderunner.CodeRunner, err error) {
+	// Read and analyse code
+	var code *code.Code
+	code, err = ReadCode(path, debugFlag, level)
+	if err != nil {
+		return
+	}
+
+	// Create code runner
+	ret = coderunner.New(code, debugFlag, options)
+
+	return
+}
+
+// ReadCode reads the code from the given file path and analyses it with the given optimisation level.
+func ReadCode(path string, debugFlag bool, level codeanalyser.OptLevel) (ret *code.Code, err error) {
 	// Read file
 	codeBytes, err := os.ReadFile(path)
 	if err != nil {
@@ -35,14 +53,39 @@ func Read(path string, debugFlag bool) (ret *coderunner.CodeRunner, err error) {
 	codeText := string(codeBytes)
 
 	// Analyse code
-	var code *code.Code
-	code, err = codeanalyser.Analyse(codeText, debugFlag)
+	ret, err = codeanalyser.Analyse(codeText, debugFlag, level)
+	return
+}
+
+// Build reads and analyses the code from the given file path like ReadCode, and returns it as
+// bytecode with the hash of its source.
+func Build(path string, debugFlag bool, level codeanalyser.OptLevel) (ret *bytecode.File, err error) {
+	// Read file
+	codeBytes, err := os.ReadFile(path)
 	if err != nil {
 		return
 	}
 
-	// Create code runner
-	ret = coderunner.New(code, debugFlag)
-
+	// Analyse code
+	code, err := codeanalyser.Analyse(string(codeBytes), debugFlag, level)
+	if err != nil {
+		return
+	}
+	ret = &bytecode.File{
+		Code:       code,
+		Level:      level,
+		SourceHash: sha256.Sum256(codeBytes),
+	}
 	return
 }
+
+// ReadBytecode reads a bytecode file written by bytecode.Write from the given file path.
+func ReadBytecode(path string) (ret *bytecode.File, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	return bytecode.Read(file)
+}
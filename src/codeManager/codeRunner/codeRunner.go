@@ -18,10 +18,18 @@
 package coderunner
 
 import (
+	"bufio"
+	"cmp"
 	"fmt"
+	"io"
+	"math/bits"
+	"os"
 	"slices"
+	"strings"
 
 	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/expression"
+	"github.com/Anslen/Bfck/codeManager/runtimeError"
 	"github.com/Anslen/Bfck/memory"
 )
 
@@ -34,200 +42,386 @@ const (
 	ReturnReachWatch
 	ReturnReachUntil
 	ReturnReachStop
+	ReturnRuntimeError         // Details can be got by RuntimeError
+	ReturnReachHistoryStart    // Running backwards reached the first operator
 	returnAfterExecuteOperator // For internal function executeOperator
 )
 
+type EOFPolicy byte
+
+// EOF policies decide what ',' stores when input is exhausted.
+const (
+	EOFZero      = iota // Store 0
+	EOFUnchanged        // Leave cell unchanged
+	EOFMinusOne         // Store -1, which is the maximum value of cell width
+)
+
+// Options configures a CodeRunner, zero value of each field means default.
+type Options struct {
+	Input  io.Reader     // Source of ',' operator, default os.Stdin
+	Output io.Writer     // Destination of '.' operator, default os.Stdout
+	EOF    EOFPolicy     // Behaviour of ',' at end of input, default EOFZero
+	Memory memory.Config // Cell width, overflow policy and tape limits, default unbounded 8-bit wrapping cells
+}
+
+// Engine runs analysed code from the beginning without debugging, CodeRunner is the default one.
+type Engine interface {
+	Run() ReturnCode
+	RuntimeError() error // Set when Run returns ReturnRuntimeError
+}
+
+// breakPoint stops running before an operator if its condition is true.
+//
+// It is set at the begin of a line if column is 0, at a line and column if line is not 0, otherwise at index.
+type breakPoint struct {
+	id        int // Number shown to users, never reused
+	index     int // Operator to stop before
+	line      uint64
+	column    int
+	condition *expression.Expr // nil means always stop
+	enabled   bool
+	hits      int64 // Times reached while enabled since the code starts running, up to the current step
+	ignore    int64 // Times left to pass without stopping when it would stop
+}
+
+// location returns where the breakpoint was set, like "line 3", "line 3:5" or "operator 7".
+func (bp *breakPoint) location() string {
+	switch {
+	case bp.line == 0:
+		return fmt.Sprintf("operator %v", bp.index)
+
+	case bp.column == 0:
+		return fmt.Sprintf("line %v", bp.line)
+	}
+	return fmt.Sprintf("line %v:%v", bp.line, bp.column)
+}
+
+// compare orders breakpoints by operator index, then by where they were set.
+func (bp *breakPoint) compare(other *breakPoint) int {
+	return cmp.Or(cmp.Compare(bp.index, other.index), cmp.Compare(bp.line, other.line), cmp.Compare(bp.column, other.column))
+}
+
 type CodeRunner struct {
 	code               *code.Code
-	codeIndex          int // Point at next operator to execute
-	memory             *memory.Memory
-	memoryPointer      int
+	input              *bufio.Reader
+	output             *bufio.Writer // Flushed on finish, breakpoints and input requests
+	eofPolicy          EOFPolicy
+	memoryConfig       memory.Config
+	runtimeErr         error // Set when returning ReturnRuntimeError
+	codeIndex          int   // Point at next operator to execute
+	memory             memory.Tape
 	debugFlag          bool
-	breakPoint         []uint64
-	codeBreakPointed   []bool
+	breakPoint         []breakPoint // Sorted by location
+	codeBreakPointed   []bool       // Operators with enabled breakpoints
 	breakPointUsed     bool
-	watchAddress       []int
-	watchUsed          bool
-	watchChecked       bool
-	watchHit           bool
+	breakPointCount    int          // Breakpoints ever added, for ids
+	breakPointReport   string       // Breakpoints stopped at by the last ReturnReachBreakPoint
+	watchPoint         []watchPoint // Sorted by address
+	watchReport        string       // Fired watchpoints of the last ReturnReachWatch
 	untilStatus        bool
 	stopEnabled        bool
 	stopIndex          int
 	untilEnabled       bool
-	infiniteLoopWarned bool // Only warn once to prevent flooding
+	stepping           bool     // Executing by Step, optimised loops are stepped into
+	history            *history // Executed operators for running backwards, only in debug mode
+	infiniteLoopWarned bool     // Only warn once to prevent flooding
 }
 
-func New(code *code.Code, debugFlag bool) (ret *CodeRunner) {
+func New(code *code.Code, debugFlag bool, options Options) (ret *CodeRunner) {
 	if code == nil {
 		panic("CodeRunner: code is nil")
 	}
 
+	// Use standard streams by default
+	if options.Input == nil {
+		options.Input = os.Stdin
+	}
+	if options.Output == nil {
+		options.Output = os.Stdout
+	}
+	if !memory.IsValidCellWidth(options.Memory.CellWidth) {
+		panic("CodeRunner: unsupported cell width")
+	}
+
 	if debugFlag {
 		ret = &CodeRunner{
 			code:             code,
-			memory:           memory.New(),
+			input:            bufio.NewReader(options.Input),
+			output:           bufio.NewWriter(options.Output),
+			eofPolicy:        options.EOF,
+			memoryConfig:     options.Memory,
+			memory:           memory.New(options.Memory),
 			debugFlag:        true,
-			breakPoint:       make([]uint64, 0),
+			breakPoint:       make([]breakPoint, 0),
 			codeBreakPointed: make([]bool, code.CodeCount),
-			watchAddress:     make([]int, 0),
+			watchPoint:       make([]watchPoint, 0),
 		}
+		ret.history = newHistory(ret.memory)
 	} else {
 		ret = &CodeRunner{
-			code:   code,
-			memory: memory.New(),
+			code:         code,
+			input:        bufio.NewReader(options.Input),
+			output:       bufio.NewWriter(options.Output),
+			eofPolicy:    options.EOF,
+			memoryConfig: options.Memory,
+			memory:       memory.New(options.Memory),
 		}
 	}
 	return
 }
 
-// AddBreakPoint adds a breakpoint at the specified line.
-func (cr *CodeRunner) AddBreakPoint(line uint64) (message string) {
+// ParseEOFPolicy converts text like "0", "unchanged" or "-1" to EOFPolicy.
+func ParseEOFPolicy(text string) (ret EOFPolicy, err error) {
+	switch text {
+	case "0", "zero":
+		ret = EOFZero
+
+	case "unchanged", "keep":
+		ret = EOFUnchanged
+
+	case "-1", "255":
+		ret = EOFMinusOne
+
+	default:
+		err = fmt.Errorf("Error: unknown EOF policy %q, expect 0, unchanged or -1", text)
+	}
+	return
+}
+
+// SetEOFPolicy sets what ',' stores when input is exhausted.
+func (cr *CodeRunner) SetEOFPolicy(policy EOFPolicy) {
+	cr.eofPolicy = policy
+}
+
+// AddBreakPoint adds a breakpoint at the specified line, stopping only when condition is true unless it is nil.
+//
+// Adding a condition to an existing breakpoint replaces its condition.
+func (cr *CodeRunner) AddBreakPoint(line uint64, condition *expression.Expr) (message string) {
 	if !cr.debugFlag {
 		panic("CodeRunner: can't add breakpoint when not in debug mode")
 	}
 
 	// Check line range
 	if line == 0 || line > cr.code.LineCount {
-		message = fmt.Sprintf("Error: breakpoint out of range, line count is %v, get line %v", cr.code.LineCount, line)
+		message = fmt.Sprintf("Error: breakpoint out of range, line count is %v, get line %v\n\n", cr.code.LineCount, line)
 		return
 	}
 
-	// Find position and add breakpoint
-	position, found := slices.BinarySearch(cr.breakPoint, line)
-	if !found {
-		// Warn if breakpoint at empty line
-		if cr.code.LineBegins[line-1] == -1 {
-			message = fmt.Sprintf("Warning: breakpoint at line %v will not work\n\n", line)
-			return
-		}
-
-		cr.codeBreakPointed[cr.code.LineBegins[line-1]] = true
-		cr.breakPoint = slices.Insert(cr.breakPoint, position, line)
-		// Pirnt add success information
-		message = fmt.Sprintf("Breakpoint added at line %v\n\n", line)
-
-	} else {
-		message = fmt.Sprintf("Warning: Breakpoint at line %v already existed\n\n", line)
+	// Warn if breakpoint at empty line
+	if cr.code.LineBegins[line-1] == -1 {
+		message = fmt.Sprintf("Warning: breakpoint at line %v will not work\n\n", line)
+		return
 	}
 
-	return
+	return cr.addBreakPoint(breakPoint{index: cr.code.LineBegins[line-1], line: line, condition: condition})
 }
 
-// RemoveBreakPoint removes the breakpoint at the specified index.
+// AddColumnBreakPoint adds a breakpoint at the first operator from the specified line and column.
 //
-// CAUSION: index start from 1
-func (cr *CodeRunner) RemoveBreakPoint(index int) (message string) {
+// CAUSION: column start from 1 and counts runes
+func (cr *CodeRunner) AddColumnBreakPoint(line uint64, column int, condition *expression.Expr) (message string) {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't remove breakpoint when not in debug mode")
+		panic("CodeRunner: can't add breakpoint when not in debug mode")
 	}
 
-	// Check index range
-	if index <= 0 || index > len(cr.breakPoint) {
-		message = fmt.Sprintf("Error: breakpoint index out of range, get %v, breakpoint count is %v", index, len(cr.breakPoint))
+	// Check line and column range
+	if line == 0 || line > cr.code.LineCount {
+		message = fmt.Sprintf("Error: breakpoint out of range, line count is %v, get line %v\n\n", cr.code.LineCount, line)
+		return
+	}
+	if column == 0 {
+		message = "Error: breakpoint column start from 1\n\n"
 		return
 	}
 
-	// Remove success information
-	message = fmt.Sprintf("Breakpoint %v removed\n\n", index)
+	// Find the first operator at or after the position
+	var found int = -1
+	for index := range cr.code.CodeCount {
+		if cr.comparePosition(index, line, column) < 0 {
+			continue
+		}
+		if found == -1 || cr.comparePosition(index, uint64(cr.code.SourceLines[found]), cr.code.SourceColumns[found]) < 0 {
+			found = index
+		}
+	}
+	if found == -1 {
+		message = fmt.Sprintf("Warning: breakpoint at line %v:%v will not work\n\n", line, column)
+		return
+	}
 
-	// Remove breakpoint
-	var removedLine uint64 = cr.breakPoint[index-1]
-	var removedCodeIndex int = cr.code.LineBegins[removedLine-1]
-	cr.breakPoint = slices.Delete(cr.breakPoint, index-1, index)
+	return cr.addBreakPoint(breakPoint{index: found, line: line, column: column, condition: condition})
+}
 
-	// Remove code breakpoint mark if nessesary
-	// Check last breakpoint
-	if index != 1 && cr.code.LineBegins[cr.breakPoint[index-2]-1] == removedCodeIndex {
-		return
+// AddOperatorBreakPoint adds a breakpoint at the specified operator index.
+func (cr *CodeRunner) AddOperatorBreakPoint(index int, condition *expression.Expr) (message string) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't add breakpoint when not in debug mode")
 	}
-	// Check next breakpoint
-	if index != len(cr.breakPoint)+1 && cr.code.LineBegins[cr.breakPoint[index-1]-1] == removedCodeIndex {
+
+	// Check index range
+	if index < 0 || index >= cr.code.CodeCount {
+		message = fmt.Sprintf("Error: breakpoint out of range, operator count is %v, get operator %v\n\n", cr.code.CodeCount, index)
 		return
 	}
 
-	cr.codeBreakPointed[removedCodeIndex] = false
+	return cr.addBreakPoint(breakPoint{index: index, condition: condition})
+}
+
+// addBreakPoint inserts the breakpoint in order, or replaces the condition of the one at the same location.
+func (cr *CodeRunner) addBreakPoint(added breakPoint) (message string) {
+	position, found := slices.BinarySearchFunc(cr.breakPoint, &added, func(each breakPoint, added *breakPoint) int {
+		return each.compare(added)
+	})
+
+	switch {
+	case !found:
+		cr.breakPointCount++
+		added.id = cr.breakPointCount
+		added.enabled = true
+		cr.codeBreakPointed[added.index] = true
+		cr.breakPoint = slices.Insert(cr.breakPoint, position, added)
+		// Pirnt add success information
+		if added.line != 0 {
+			message = fmt.Sprintf("Breakpoint %v added at %v (operator %v)", added.id, added.location(), added.index)
+		} else {
+			message = fmt.Sprintf("Breakpoint %v added at %v", added.id, added.location())
+		}
+		if added.condition != nil {
+			message += fmt.Sprintf(" if %v", added.condition)
+		}
+		message += "\n\n"
+
+	case added.condition != nil:
+		cr.breakPoint[position].condition = added.condition
+		message = fmt.Sprintf("Condition of breakpoint %v at %v set to %v\n\n", cr.breakPoint[position].id, added.location(), added.condition)
+
+	default:
+		message = fmt.Sprintf("Warning: Breakpoint %v at %v already existed\n\n", cr.breakPoint[position].id, added.location())
+	}
 	return
 }
 
-// ClearBreakPoints removes all breakpoints.
-func (cr *CodeRunner) ClearBreakPoints() {
+// comparePosition compares the source position of the operator at index with the given line and column.
+func (cr *CodeRunner) comparePosition(index int, line uint64, column int) int {
+	return cmp.Or(cmp.Compare(uint64(cr.code.SourceLines[index]), line), cmp.Compare(cr.code.SourceColumns[index], column))
+}
+
+// RemoveBreakPoint removes the breakpoint with the specified id.
+func (cr *CodeRunner) RemoveBreakPoint(id int) (message string) {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't clear breakpoints when not in debug mode")
+		panic("CodeRunner: can't remove breakpoint when not in debug mode")
 	}
-	cr.breakPoint = make([]uint64, 0)
-	cr.codeBreakPointed = make([]bool, cr.code.CodeCount)
+
+	var position int = cr.findBreakPoint(id)
+	if position == -1 {
+		message = fmt.Sprintf("Error: No breakpoint number %v\n\n", id)
+		return
+	}
+
+	// Remove success information
+	message = fmt.Sprintf("Breakpoint %v removed\n\n", id)
+
+	// Remove breakpoint
+	var removedCodeIndex int = cr.breakPoint[position].index
+	cr.breakPoint = slices.Delete(cr.breakPoint, position, position+1)
+	cr.markBreakPointed(removedCodeIndex)
+	return
 }
 
-// PrintBreakPoint prints all breakpoints and watching information.
-func (cr *CodeRunner) PrintBreakPoints() {
+// EnableBreakPoint enables or disables the breakpoint with the specified id, disabled ones keep
+// their settings but neither stop nor count hits.
+func (cr *CodeRunner) EnableBreakPoint(id int, enabled bool) (message string) {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't print breakpoints when not in debug mode")
+		panic("CodeRunner: can't enable breakpoint when not in debug mode")
 	}
 
-	// Print breakpoint info
-	if len(cr.breakPoint) == 0 {
-		fmt.Print("No breakpoints exist now.\n\n")
-	} else {
-		// Print each breakpoint
-		fmt.Println("Breakpoints:")
-		fmt.Println("Num\tLine")
-		for index, line := range cr.breakPoint {
-			fmt.Printf("%v\t%v\n", index+1, line)
-		}
-		fmt.Print("\n")
+	var position int = cr.findBreakPoint(id)
+	if position == -1 {
+		message = fmt.Sprintf("Error: No breakpoint number %v\n\n", id)
+		return
 	}
-}
 
-// AddWatch sets a watch on the memory byte at the current pointer plus the given offset.
-func (cr *CodeRunner) AddWatch(address int) (message string) {
-	var (
-		position int
-		found    bool
-	)
-	position, found = slices.BinarySearch(cr.watchAddress, address)
-	if found {
-		message = fmt.Sprintf("Warning: Address %v is already being watched\n\n", address)
+	cr.breakPoint[position].enabled = enabled
+	cr.markBreakPointed(cr.breakPoint[position].index)
+	if enabled {
+		message = fmt.Sprintf("Breakpoint %v enabled\n\n", id)
 	} else {
-		cr.watchAddress = slices.Insert(cr.watchAddress, position, address)
-		message = fmt.Sprintf("Watching memory %v\n\n", address)
+		message = fmt.Sprintf("Breakpoint %v disabled\n\n", id)
 	}
 	return
 }
 
-func (cr *CodeRunner) RemoveWatch(index int) (message string) {
-	if index <= 0 || index > len(cr.watchAddress) {
-		message = fmt.Sprintf("Error: Watchpoint index out of range, get %v, watchpoint count is %v\n\n", index, len(cr.watchAddress))
+// IgnoreBreakPoint makes the breakpoint with the specified id pass the next count times it would stop.
+func (cr *CodeRunner) IgnoreBreakPoint(id int, count int64) (message string) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't ignore breakpoint when not in debug mode")
+	}
+
+	var position int = cr.findBreakPoint(id)
+	if position == -1 {
+		message = fmt.Sprintf("Error: No breakpoint number %v\n\n", id)
 		return
 	}
 
-	message = fmt.Sprintf("Watchpoint %v at address %v removed\n\n", index, cr.watchAddress[index-1])
-	cr.watchAddress = slices.Delete(cr.watchAddress, index-1, index)
+	cr.breakPoint[position].ignore = count
+	if count == 0 {
+		message = fmt.Sprintf("Breakpoint %v will stop next time it is reached\n\n", id)
+	} else {
+		message = fmt.Sprintf("Breakpoint %v will ignore next %v crossings\n\n", id, count)
+	}
 	return
 }
 
-func (cr *CodeRunner) ClearWatches() {
+// findBreakPoint returns the position of the breakpoint with the specified id, -1 if not found.
+func (cr *CodeRunner) findBreakPoint(id int) int {
+	return slices.IndexFunc(cr.breakPoint, func(each breakPoint) bool {
+		return each.id == id
+	})
+}
+
+// markBreakPointed updates the mark of the operator at index after its breakpoints changed.
+func (cr *CodeRunner) markBreakPointed(index int) {
+	cr.codeBreakPointed[index] = slices.ContainsFunc(cr.breakPoint, func(each breakPoint) bool {
+		return each.index == index && each.enabled
+	})
+}
+
+// ClearBreakPoints removes all breakpoints.
+func (cr *CodeRunner) ClearBreakPoints() {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't clear watchpoints when not in debug mode")
+		panic("CodeRunner: can't clear breakpoints when not in debug mode")
 	}
-
-	cr.watchAddress = make([]int, 0)
+	cr.breakPoint = make([]breakPoint, 0)
+	cr.codeBreakPointed = make([]bool, cr.code.CodeCount)
 }
 
-// PrintWatchInfo prints all watchpoints information.
-func (cr *CodeRunner) PrintWatchInfo() {
+// PrintBreakPoint prints all breakpoints and watching information.
+func (cr *CodeRunner) PrintBreakPoints() {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't print watchpoints when not in debug mode")
+		panic("CodeRunner: can't print breakpoints when not in debug mode")
 	}
 
-	// Print watch info
-	if len(cr.watchAddress) == 0 {
-		fmt.Print("No watchpoints exist now.\n\n")
+	// Print breakpoint info
+	if len(cr.breakPoint) == 0 {
+		fmt.Print("No breakpoints exist now.\n\n")
 	} else {
-		// Print each watchpoint
-		fmt.Println("Watch address:")
-		fmt.Println("Num\tAddress")
-		for index, address := range cr.watchAddress {
-			fmt.Printf("%v\t%v\n", index+1, address)
+		// Print each breakpoint by id
+		var sorted []breakPoint = slices.SortedFunc(slices.Values(cr.breakPoint), func(a, b breakPoint) int {
+			return cmp.Compare(a.id, b.id)
+		})
+		fmt.Println("Breakpoints:")
+		fmt.Printf("%-6v%-5v%-8v%-8v%-10v%-16v%v\n", "Num", "Enb", "Hits", "Ignore", "Operator", "Location", "Condition")
+		for _, each := range sorted {
+			var enabled string = "n"
+			if each.enabled {
+				enabled = "y"
+			}
+			var condition string
+			if each.condition != nil {
+				condition = each.condition.String()
+			}
+			var line string = fmt.Sprintf("%-6v%-5v%-8v%-8v%-10v%-16v%v", each.id, enabled, each.hits, each.ignore, each.index, each.location(), condition)
+			fmt.Println(strings.TrimRight(line, " "))
 		}
 		fmt.Print("\n")
 	}
@@ -282,14 +476,29 @@ func (cr *CodeRunner) PrintNextOperator() {
 
 // GetMemoryPointer returns the current memory pointer.
 func (cr *CodeRunner) GetMemoryPointer() int {
-	return cr.memoryPointer
+	return cr.memory.Pointer()
 }
 
-// PeekBytes peeks bytes from memory with the given offset and length.
+// PeekCells peeks cells from memory with the given offset and length.
 //
 // Offset is relative to the current memory pointer.
-func (cr *CodeRunner) PeekBytes(offset, length int) (ret []byte) {
-	return cr.memory.PeekBytes(offset, length)
+func (cr *CodeRunner) PeekCells(offset, length int) (ret []uint32) {
+	return cr.memory.PeekCells(offset, length)
+}
+
+// Finished reports whether every operator has been executed.
+func (cr *CodeRunner) Finished() bool {
+	return cr.codeIndex >= cr.code.CodeCount
+}
+
+// BreakPointReport returns the breakpoints stopped at for the last ReturnReachBreakPoint, one per line.
+func (cr *CodeRunner) BreakPointReport() string {
+	return cr.breakPointReport
+}
+
+// RuntimeError returns the error which caused the last ReturnRuntimeError, nil if no error occurred.
+func (cr *CodeRunner) RuntimeError() error {
+	return cr.runtimeErr
 }
 
 // EnableUntil enables the until mode.
@@ -312,18 +521,31 @@ func (cr *CodeRunner) Run() (ret ReturnCode) {
 
 // Continue continues running the code from the current position.
 func (cr *CodeRunner) Continue() (ret ReturnCode) {
+	// Flush output whenever running pauses or finishes
+	defer cr.output.Flush()
+
+	// Watchpoint may stop after the last operator
+	if cr.Finished() {
+		return ReturnAfterFinish
+	}
+
 	for {
-		// Check for breakpoint
+		// Check for breakpoint, hits counted here before running backwards are not checked again
 		if cr.breakPointUsed {
 			cr.breakPointUsed = false
-		} else if cr.debugFlag && cr.codeBreakPointed[cr.codeIndex] {
+		} else if cr.debugFlag && cr.codeBreakPointed[cr.codeIndex] && !cr.history.counted() && cr.isBreakPointHit(true) {
 			// Hit breakpoint
 			cr.breakPointUsed = true
 			return ReturnReachBreakPoint
 		}
 
-		// Execute operator
-		if ret = cr.executeOperator(); ret != returnAfterExecuteOperator {
+		// Execute operator, branching here since execute is too large to inline
+		if cr.debugFlag {
+			ret = cr.executeRecorded()
+		} else {
+			ret = cr.executeOperator()
+		}
+		if ret != returnAfterExecuteOperator {
 			return ret
 		}
 	}
@@ -337,7 +559,10 @@ func (cr *CodeRunner) Step() (ret ReturnCode) {
 	}
 
 	// Execute operator
-	ret = cr.executeOperator()
+	cr.stepping = true
+	ret = cr.execute()
+	cr.stepping = false
+	cr.output.Flush()
 
 	// Change return code if after execute operator
 	if ret == returnAfterExecuteOperator {
@@ -351,63 +576,65 @@ func (cr *CodeRunner) Step() (ret ReturnCode) {
 func (cr *CodeRunner) Reset() {
 	// Reset code index and memory
 	cr.codeIndex = 0
-	cr.memory = memory.New()
-	cr.memoryPointer = 0
+	cr.memory = memory.New(cr.memoryConfig)
+	cr.runtimeErr = nil
+	if cr.debugFlag {
+		cr.history.reset(cr.memory)
+	}
 
-	// Clear debug flags
+	// Clear debug flags and hit counts
+	for index := range cr.breakPoint {
+		cr.breakPoint[index].hits = 0
+	}
 	cr.breakPointUsed = false
-	cr.watchUsed = false
 	cr.untilEnabled = false
 }
 
 // executeOperator executes the current operator and advances the code index.
 func (cr *CodeRunner) executeOperator() (ret ReturnCode) {
 	// Check stop point
-	if cr.debugFlag && cr.stopEnabled && cr.codeIndex == cr.stopIndex {
+	if cr.debugFlag && cr.stopEnabled && cr.codeIndex == cr.stopIndex && !cr.history.rewinding {
 		cr.stopEnabled = false
 		return ReturnReachStop
 	}
 
-	// fetch operator and auxiliary data
+	// fetch operator, auxiliary data and offset
 	var operator code.Operator = cr.code.Operators[cr.codeIndex]
 	var auxiliary uint64 = cr.code.Auxiliary[cr.codeIndex]
+	var offset int = cr.code.Offsets[cr.codeIndex]
 	cr.codeIndex++
 
 	// Execute operator
 	switch operator {
 	case code.OpAdd:
-		// Check watchpoint
-		if cr.debugFlag && cr.isWatchHit() {
-			cr.codeIndex--
-			return ReturnReachWatch
-		}
-
 		// Execute addition
-		cr.memory.Add(auxiliary)
-		cr.watchUsed = false
-
-	case code.OpSub:
-		// Check watchpoint
-		if cr.debugFlag && cr.isWatchHit() {
-			cr.codeIndex--
-			return ReturnReachWatch
+		if ret = cr.reach(offset); ret != returnAfterExecuteOperator {
+			return ret
+		}
+		cr.remember(offset)
+		if err := cr.memory.AddAt(offset, auxiliary); err != nil {
+			return cr.raise(err)
 		}
 
+	case code.OpSub:
 		// Execute subtraction
-		cr.memory.Sub(auxiliary)
-		cr.watchUsed = false
+		if ret = cr.reach(offset); ret != returnAfterExecuteOperator {
+			return ret
+		}
+		cr.remember(offset)
+		if err := cr.memory.SubAt(offset, auxiliary); err != nil {
+			return cr.raise(err)
+		}
 
 	case code.OpMoveLeft:
-		// Memory block may change after moving pointer
-		cr.memory = cr.memory.MovePtr(-int(auxiliary))
-		cr.memoryPointer -= int(auxiliary)
-		cr.watchChecked = false
+		if ret = cr.movePointer(-int(auxiliary)); ret != returnAfterExecuteOperator {
+			return ret
+		}
 
 	case code.OpMoveRight:
-		// Memory block may change after moving pointer
-		cr.memory = cr.memory.MovePtr(int(auxiliary))
-		cr.memoryPointer += int(auxiliary)
-		cr.watchChecked = false
+		if ret = cr.movePointer(int(auxiliary)); ret != returnAfterExecuteOperator {
+			return ret
+		}
 
 	case code.OpLeftBracket:
 		if cr.memory.Peek(0) == 0 {
@@ -418,7 +645,7 @@ func (cr *CodeRunner) executeOperator() (ret ReturnCode) {
 		if cr.memory.Peek(0) != 0 {
 			// Check infinite loop, only warn once
 			if !cr.infiniteLoopWarned && (cr.codeIndex-1 == int(auxiliary)) {
-				fmt.Printf("\nWarning: Infinite loop at operator %v\n", cr.codeIndex-1)
+				cr.warn("Infinite loop at operator %v", cr.codeIndex-1)
 				cr.infiniteLoopWarned = true
 			}
 			cr.codeIndex = int(auxiliary)
@@ -429,18 +656,58 @@ func (cr *CodeRunner) executeOperator() (ret ReturnCode) {
 		}
 
 	case code.OpInput:
-		if cr.debugFlag && cr.isWatchHit() {
-			cr.codeIndex--
-			return ReturnReachWatch
+		// Flush output before waiting for input
+		cr.output.Flush()
+		cr.readInput()
+
+	case code.OpClear:
+		if ret = cr.reach(offset); ret != returnAfterExecuteOperator {
+			return ret
+		}
+		var enterLoop bool = cr.memory.Peek(offset) != 0
+		if ret = cr.clearCell(auxiliary, offset); ret != returnAfterExecuteOperator {
+			return ret
+		}
+
+		// Loop ends here like its right bracket
+		if enterLoop && cr.untilEnabled && cr.memory.Peek(offset) == 0 {
+			cr.untilEnabled = false
+			return ReturnReachUntil
 		}
 
-		var input rune
-		fmt.Scanf("%c", &input)
-		cr.memory.Poke(byte(input))
-		cr.watchUsed = false
+	case code.OpScan:
+		var enterLoop bool = cr.memory.Peek(0) != 0
+		if err := cr.memory.Scan(int(int64(auxiliary))); err != nil {
+			return cr.raise(err)
+		}
+
+		// Loop ends here like its right bracket
+		if enterLoop && cr.untilEnabled {
+			cr.untilEnabled = false
+			return ReturnReachUntil
+		}
+
+	case code.OpSet:
+		// Same as clear followed by addition
+		if ret = cr.reach(offset); ret != returnAfterExecuteOperator {
+			return ret
+		}
+		cr.remember(offset)
+		cr.memory.PokeAt(offset, 0)
+		if err := cr.memory.AddAt(offset, auxiliary); err != nil {
+			return cr.raise(err)
+		}
+
+	case code.OpMultiply:
+		if ret = cr.multiply(auxiliary); ret != returnAfterExecuteOperator {
+			return ret
+		}
 
 	case code.OpOutput:
-		fmt.Printf("%c", cr.memory.Peek(0))
+		// Only the lowest byte is written for wide cells, replayed steps wrote it before
+		if !cr.debugFlag || !cr.history.replaying() {
+			cr.output.WriteByte(byte(cr.memory.Peek(0)))
+		}
 	}
 
 	if cr.codeIndex >= cr.code.CodeCount {
@@ -450,26 +717,244 @@ func (cr *CodeRunner) executeOperator() (ret ReturnCode) {
 	}
 }
 
-// isWatchHit checks if the current memory pointer hits any watchpoint.
-func (cr *CodeRunner) isWatchHit() bool {
+// movePointer moves memory pointer by the given offset, raising runtime error if tape limits are broken.
+func (cr *CodeRunner) movePointer(offset int) (ret ReturnCode) {
+	if err := cr.memory.MovePtr(offset); err != nil {
+		return cr.raise(err)
+	}
+	return returnAfterExecuteOperator
+}
+
+// reach makes the cell at pointer plus offset usable, raising runtime error if tape limits are broken.
+func (cr *CodeRunner) reach(offset int) (ret ReturnCode) {
+	if offset != 0 {
+		if err := cr.memory.Extend(offset, offset); err != nil {
+			return cr.raise(err)
+		}
+	}
+	return returnAfterExecuteOperator
+}
+
+// clearCell executes clear operator on the cell at pointer plus offset, auxiliary is 1 if it comes from [+].
+//
+// [+] only clears the cell by wrapping around, so with other overflow policies it runs one iteration
+// at a time and repeats itself like the original loop.
+func (cr *CodeRunner) clearCell(auxiliary uint64, offset int) (ret ReturnCode) {
+	cr.remember(offset)
+	if auxiliary == 0 || cr.memoryConfig.Overflow == memory.OverflowWrap {
+		cr.memory.PokeAt(offset, 0)
+		return returnAfterExecuteOperator
+	}
+
+	if cr.memory.Peek(offset) != 0 {
+		if err := cr.memory.AddAt(offset, 1); err != nil {
+			return cr.raise(err)
+		}
+		cr.codeIndex--
+	}
+	return returnAfterExecuteOperator
+}
+
+// multiply runs the multiply loop following the operator in one step, auxiliary is the index of its targets.
+//
+// The original loop runs instead if anything may stop or fail inside it, so debugging and errors
+// behave exactly as without optimisation.
+func (cr *CodeRunner) multiply(auxiliary uint64) (ret ReturnCode) {
+	var targets []code.MulTarget = cr.code.MulTargets[auxiliary]
+	var count uint64 = uint64(cr.memory.Peek(0))
+	// Left bracket of the loop is the next operator
+	var loopEnd int = int(cr.code.Auxiliary[cr.codeIndex])
+
+	if count == 0 || !cr.decideMultiply(targets, count, loopEnd) {
+		return returnAfterExecuteOperator
+	}
+
+	// Add count times factor to each target
+	cr.remember(0)
+	for _, target := range targets {
+		cr.remember(target.Offset)
+		var err error
+		if target.Factor > 0 {
+			err = cr.memory.AddAt(target.Offset, cr.scale(count, uint64(target.Factor)))
+		} else {
+			err = cr.memory.SubAt(target.Offset, cr.scale(count, uint64(-target.Factor)))
+		}
+		if err != nil {
+			panic("CodeRunner: unexpected error in multiply: " + err.Error())
+		}
+	}
+
+	// Loop cell is always 0 after loop, skip the loop
+	cr.memory.Poke(0)
+	cr.codeIndex = loopEnd
+	return returnAfterExecuteOperator
+}
+
+// decideMultiply calls canMultiply, in debug mode the decision is logged so that replaying takes the same steps.
+func (cr *CodeRunner) decideMultiply(targets []code.MulTarget, count uint64, loopEnd int) bool {
 	if !cr.debugFlag {
-		panic("CodeRunner: can't check watch hit when not in debug mode")
+		return cr.canMultiply(targets, count, loopEnd)
+	}
+	if !cr.history.decideMultiply(func() bool { return cr.canMultiply(targets, count, loopEnd) }) {
+		return false
 	}
 
-	// Check watchpoint only once after memory pointer changes
-	if !cr.watchChecked {
-		cr.watchChecked = true
-		var found bool
-		_, found = slices.BinarySearch(cr.watchAddress, cr.memoryPointer)
-		// Set watch hit status
-		cr.watchHit = found
+	// Extended by canMultiply when it was decided
+	var low int = min(targets[0].Offset, 0)
+	var high int = max(targets[len(targets)-1].Offset, 0)
+	if cr.memory.Extend(low, high) != nil {
+		panic("CodeRunner: unexpected error when replaying multiply")
 	}
+	return true
+}
 
-	// Return watch hit status, if watch used, flip the status and continue
-	if cr.watchHit {
-		cr.watchUsed = !cr.watchUsed
-		return cr.watchUsed
-	} else {
+// canMultiply checks whether a multiply loop can run in one step with the same result as the loop itself.
+func (cr *CodeRunner) canMultiply(targets []code.MulTarget, count uint64, loopEnd int) bool {
+	var low int = min(targets[0].Offset, 0)
+	var high int = max(targets[len(targets)-1].Offset, 0)
+
+	// Debug features inside the loop
+	if cr.debugFlag {
+		if cr.stepping || cr.untilEnabled {
+			return false
+		}
+		if cr.stopEnabled && cr.stopIndex >= cr.codeIndex && cr.stopIndex < loopEnd {
+			return false
+		}
+		if slices.Contains(cr.codeBreakPointed[cr.codeIndex:loopEnd], true) {
+			return false
+		}
+		var pointer int = cr.memory.Pointer()
+		for _, each := range cr.watchPoint {
+			if each.address >= pointer+low && each.address <= pointer+high {
+				return false
+			}
+		}
+	}
+
+	// Tape limits, the loop reports the error itself
+	if cr.memory.Extend(low, high) != nil {
 		return false
 	}
+
+	// Trapped overflow, the loop reports the error itself at the right iteration
+	if cr.memoryConfig.Overflow == memory.OverflowTrap {
+		var cellMax uint64 = memory.CellMax(cr.memoryConfig.CellWidth)
+		for _, target := range targets {
+			var current uint64 = uint64(cr.memory.Peek(target.Offset))
+			if target.Factor > 0 && cr.scale(count, uint64(target.Factor)) > cellMax-current {
+				return false
+			}
+			if target.Factor < 0 && cr.scale(count, uint64(-target.Factor)) > current {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// scale multiplies loop count by factor.
+//
+// When wrapping, overflow of uint64 keeps the result modulo cell width. Otherwise the result
+// stays at the maximum uint64, which is beyond any cell anyway.
+func (cr *CodeRunner) scale(count, factor uint64) uint64 {
+	hi, lo := bits.Mul64(count, factor)
+	if hi != 0 && cr.memoryConfig.Overflow != memory.OverflowWrap {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+// warn writes a warning to stderr after flushing output, so that it follows the output written before.
+func (cr *CodeRunner) warn(format string, args ...any) {
+	cr.output.Flush()
+	fmt.Fprintf(os.Stderr, "\nWarning: "+format+"\n", args...)
+}
+
+// raise records a runtime error caused by the operator just fetched and steps back to it.
+func (cr *CodeRunner) raise(err error) (ret ReturnCode) {
+	cr.codeIndex--
+	cr.runtimeErr = runtimeError.New(cr.codeIndex, cr.code.SourceLines[cr.codeIndex], err)
+	return ReturnRuntimeError
+}
+
+// readInput reads one byte into the current cell, applying EOF policy when input is exhausted.
+func (cr *CodeRunner) readInput() {
+	var input byte
+	var ok bool
+	if cr.debugFlag {
+		cr.remember(0)
+		input, ok = cr.history.readInput(cr.input)
+	} else {
+		value, err := cr.input.ReadByte()
+		input, ok = value, err == nil
+	}
+	if ok {
+		cr.memory.Poke(uint32(input))
+		return
+	}
+
+	switch cr.eofPolicy {
+	case EOFZero:
+		cr.memory.Poke(0)
+
+	case EOFMinusOne:
+		// Truncated to all ones of cell width
+		cr.memory.Poke(0xFFFFFFFF)
+
+	case EOFUnchanged:
+		// Leave cell unchanged
+	}
+}
+
+// isBreakPointHit checks whether any enabled breakpoint at the current operator stops running,
+// describing them in breakPointReport.
+//
+// If count is set, hits are counted and ignore counts are used up, otherwise they are left alone.
+// A condition which fails to evaluate is reported and stops running.
+func (cr *CodeRunner) isBreakPointHit(count bool) (ret bool) {
+	var env expression.Env = expression.Env{
+		Pointer: cr.memory.Pointer(),
+		Peek: func(address int) uint32 {
+			return cr.memory.Peek(address - cr.memory.Pointer())
+		},
+	}
+
+	// Every breakpoint here is checked so that all of them count the hit
+	var report strings.Builder
+	for index := range cr.breakPoint {
+		var each *breakPoint = &cr.breakPoint[index]
+		if each.index != cr.codeIndex || !each.enabled {
+			continue
+		}
+		if count {
+			each.hits++
+		}
+
+		var hit bool = true
+		var err error
+		if each.condition != nil {
+			env.Hits = each.hits
+			if hit, err = each.condition.IsTrue(&env); err != nil {
+				cr.warn("condition of breakpoint %v at %v: %v", each.id, each.location(), err)
+				hit = true
+			}
+		}
+		var usedIgnore bool = hit && count && each.ignore > 0 && err == nil
+		if usedIgnore {
+			each.ignore--
+			hit = false
+		}
+		if count {
+			cr.history.countHit(each.id, usedIgnore)
+		}
+
+		if hit {
+			ret = true
+			fmt.Fprintf(&report, "Breakpoint %v at %v, hits %v\n", each.id, each.location(), each.hits)
+		}
+	}
+	cr.breakPointReport = report.String()
+	return
 }
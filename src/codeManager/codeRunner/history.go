@@ -0,0 +1,469 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner
+
+import (
+	"bufio"
+	"fmt"
+	"slices"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/memory"
+)
+
+const (
+	historyLimit       = 1 << 20 // Steps kept in history, older ones are rebuilt from checkpoints
+	checkpointInterval = 1 << 16 // Initial steps between checkpoints
+	maxCheckpoints     = 64      // Every other checkpoint is dropped beyond it, doubling the interval
+)
+
+// historyEntry records the state before one executed operator.
+type historyEntry struct {
+	index   int // Operator index
+	pointer int // Memory pointer
+	changes int // Index of its first cellChange
+}
+
+// cellChange records the value of a cell before an operator changed it.
+type cellChange struct {
+	address int
+	value   uint32
+}
+
+// hitCount records a breakpoint hit counted at a step, so that undoing the step takes it back.
+type hitCount struct {
+	step       int
+	id         int  // Breakpoint number
+	usedIgnore bool // Whether the hit used up an ignore count
+}
+
+// checkpoint is a copy of the whole state at a step, history before it can be rebuilt by replaying from it.
+type checkpoint struct {
+	step        int
+	index       int
+	memory      memory.Tape
+	inputPos    int
+	multiplyPos int
+}
+
+// history records executed operators in debug mode so that they can be undone.
+//
+// Steps undone are executed again when running forward, taking input and multiply decisions
+// from the logs and writing no output until the frontier is reached.
+type history struct {
+	entries     []historyEntry // Last steps before the current one
+	changes     []cellChange
+	steps       int   // Steps executed since reset, which is also the current step
+	frontier    int   // Steps ever executed, steps before it are replayed
+	inputLog    []int // Bytes read by input operators, -1 for end of input
+	inputPos    int
+	multiplyLog []bool // Whether each multiply operator with non-zero loop cell ran in one step
+	multiplyPos int
+	hitLog      []hitCount // Breakpoint hits in order of steps
+	checkpoints []checkpoint
+	interval    int
+	pending     historyEntry // Entry of the operator being executed
+	rewinding   bool         // Replaying from a checkpoint, debug features are ignored
+}
+
+// newHistory creates an empty history starting at the given tape.
+func newHistory(tape memory.Tape) (ret *history) {
+	ret = &history{}
+	ret.reset(tape)
+	return
+}
+
+// reset clears history, the given tape becomes the checkpoint of step 0.
+func (h *history) reset(tape memory.Tape) {
+	h.entries = h.entries[:0]
+	h.changes = h.changes[:0]
+	h.steps = 0
+	h.frontier = 0
+	h.inputLog = h.inputLog[:0]
+	h.inputPos = 0
+	h.multiplyLog = h.multiplyLog[:0]
+	h.multiplyPos = 0
+	h.hitLog = h.hitLog[:0]
+	h.checkpoints = []checkpoint{{memory: tape.Clone()}}
+	h.interval = checkpointInterval
+}
+
+// replaying reports whether the current step was executed before.
+func (h *history) replaying() bool {
+	return h.steps < h.frontier
+}
+
+// begin starts recording the operator at the given index.
+func (h *history) begin(index, pointer int) {
+	h.pending = historyEntry{index: index, pointer: pointer, changes: len(h.changes)}
+}
+
+// remember records the value of a cell before the pending operator changes it.
+func (h *history) remember(address int, value uint32) {
+	h.changes = append(h.changes, cellChange{address: address, value: value})
+}
+
+// commit finishes recording the pending operator, it only becomes a step if anything changed.
+func (h *history) commit(changed bool) bool {
+	if !changed && len(h.changes) == h.pending.changes {
+		return false
+	}
+
+	h.entries = append(h.entries, h.pending)
+	h.steps++
+	h.frontier = max(h.frontier, h.steps)
+
+	// Drop the older half, checkpoints cover it
+	if len(h.entries) > historyLimit {
+		var dropped int = len(h.entries) / 2
+		var base int = h.entries[dropped].changes
+		h.changes = slices.Delete(h.changes, 0, base)
+		h.entries = slices.Delete(h.entries, 0, dropped)
+		for i := range h.entries {
+			h.entries[i].changes -= base
+		}
+	}
+	return true
+}
+
+// discard drops the cells recorded by the pending operator, which failed without changing them.
+func (h *history) discard() {
+	h.changes = h.changes[:h.pending.changes]
+}
+
+// countHit records a breakpoint hit counted at the current step.
+func (h *history) countHit(id int, usedIgnore bool) {
+	h.hitLog = append(h.hitLog, hitCount{step: h.steps, id: id, usedIgnore: usedIgnore})
+}
+
+// counted reports whether breakpoint hits were already counted at the current step.
+func (h *history) counted() bool {
+	return len(h.hitLog) > 0 && h.hitLog[len(h.hitLog)-1].step == h.steps
+}
+
+// readInput reads a byte for the input operator, replaying the input log first.
+//
+// ok is false at end of input.
+func (h *history) readInput(input *bufio.Reader) (ret byte, ok bool) {
+	if h.inputPos == len(h.inputLog) {
+		value, err := input.ReadByte()
+		if err != nil {
+			h.inputLog = append(h.inputLog, -1)
+		} else {
+			h.inputLog = append(h.inputLog, int(value))
+		}
+	}
+
+	var value int = h.inputLog[h.inputPos]
+	h.inputPos++
+	return byte(value), value >= 0
+}
+
+// decideMultiply returns the logged decision of a multiply operator, or calls decide and logs its result.
+func (h *history) decideMultiply(decide func() bool) (ret bool) {
+	if h.multiplyPos == len(h.multiplyLog) {
+		h.multiplyLog = append(h.multiplyLog, decide())
+	}
+
+	ret = h.multiplyLog[h.multiplyPos]
+	h.multiplyPos++
+	return
+}
+
+// addCheckpoint copies the state if the last checkpoint is far enough behind.
+func (h *history) addCheckpoint(index int, tape memory.Tape) {
+	if h.steps < h.checkpoints[len(h.checkpoints)-1].step+h.interval {
+		return
+	}
+
+	h.checkpoints = append(h.checkpoints, checkpoint{
+		step:        h.steps,
+		index:       index,
+		memory:      tape.Clone(),
+		inputPos:    h.inputPos,
+		multiplyPos: h.multiplyPos,
+	})
+
+	// Keep every other checkpoint, step 0 is always kept
+	if len(h.checkpoints) > maxCheckpoints {
+		var kept []checkpoint = make([]checkpoint, 0, maxCheckpoints)
+		for i := 0; i < len(h.checkpoints); i += 2 {
+			kept = append(kept, h.checkpoints[i])
+		}
+		h.checkpoints = kept
+		h.interval *= 2
+	}
+}
+
+// ReverseStep undoes the given times of executed operators, ignoring breakpoints and watchpoints.
+//
+// Returns ReturnReachHistoryStart if the first operator is reached before.
+func (cr *CodeRunner) ReverseStep(times int) (ret ReturnCode) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't step back when not in debug mode")
+	}
+	cr.startReverse()
+
+	for range times {
+		if !cr.undo() {
+			return ReturnReachHistoryStart
+		}
+	}
+	return ReturnAfterStep
+}
+
+// ReverseContinue undoes executed operators until a breakpoint or watchpoint would have stopped there.
+//
+// Returns ReturnReachHistoryStart if none is found.
+func (cr *CodeRunner) ReverseContinue() (ret ReturnCode) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't continue backwards when not in debug mode")
+	}
+
+	// Watchpoints fired by the last operator stopped here before the breakpoint
+	var atBreakPoint bool = cr.breakPointUsed
+	cr.startReverse()
+	if atBreakPoint {
+		if ret = cr.reverseWatchStop(); ret != returnAfterExecuteOperator {
+			return ret
+		}
+	}
+
+	for cr.undo() {
+		if ret = cr.reverseStop(); ret != returnAfterExecuteOperator {
+			return ret
+		}
+	}
+	return ReturnReachHistoryStart
+}
+
+// ReverseUntil undoes executed operators until the current loop is about to be entered,
+// stopping at breakpoints and watchpoints on the way.
+//
+// Prints a message and does nothing if the next operator is not inside a loop.
+func (cr *CodeRunner) ReverseUntil() (ret ReturnCode) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't run until backwards when not in debug mode")
+	}
+
+	// Find the innermost loop containing the next operator
+	var loopBegin int = -1
+	for index := range cr.codeIndex {
+		if cr.code.Operators[index] == code.OpLeftBracket && int(cr.code.Auxiliary[index]) > cr.codeIndex {
+			loopBegin = index
+		}
+	}
+	if loopBegin == -1 {
+		fmt.Print("Not inside a loop\n\n")
+		return ReturnAfterStep
+	}
+	cr.startReverse()
+
+	for cr.undo() {
+		// Left bracket is only executed when entering the loop
+		if cr.codeIndex == loopBegin {
+			return ReturnReachUntil
+		}
+		if ret = cr.reverseStop(); ret != returnAfterExecuteOperator {
+			return ret
+		}
+	}
+	return ReturnReachHistoryStart
+}
+
+// startReverse leaves until mode and forgets used breakpoint before running backwards.
+func (cr *CodeRunner) startReverse() {
+	cr.untilEnabled = false
+	cr.breakPointUsed = false
+}
+
+// reverseStop checks whether running forward would stop here at a breakpoint or after the last operator
+// at a watchpoint, marking the breakpoint as used so that continuing does not stop at it again.
+// Breakpoint hits are not counted.
+func (cr *CodeRunner) reverseStop() (ret ReturnCode) {
+	if cr.codeBreakPointed[cr.codeIndex] && cr.isBreakPointHit(false) {
+		cr.breakPointUsed = true
+		return ReturnReachBreakPoint
+	}
+	return cr.reverseWatchStop()
+}
+
+// reverseWatchStop checks whether the last executed operator fired any watchpoint.
+func (cr *CodeRunner) reverseWatchStop() (ret ReturnCode) {
+	if len(cr.watchPoint) == 0 {
+		return returnAfterExecuteOperator
+	}
+
+	entry, changes, ok := cr.lastStep()
+	if !ok {
+		return returnAfterExecuteOperator
+	}
+	if cr.watchReport = cr.firedWatches(entry, changes); cr.watchReport != "" {
+		return ReturnReachWatch
+	}
+	return returnAfterExecuteOperator
+}
+
+// execute executes the current operator, recording it in history in debug mode.
+func (cr *CodeRunner) execute() (ret ReturnCode) {
+	if !cr.debugFlag {
+		return cr.executeOperator()
+	}
+	return cr.executeRecorded()
+}
+
+// executeRecorded executes the current operator and records it in history, then checks watchpoints.
+func (cr *CodeRunner) executeRecorded() (ret ReturnCode) {
+	var index int = cr.codeIndex
+	var pointer int = cr.memory.Pointer()
+	cr.history.begin(index, pointer)
+	ret = cr.executeOperator()
+	if ret == ReturnRuntimeError {
+		// Failed operator stays the next one, it is not a step
+		cr.history.discard()
+		return
+	}
+	if !cr.history.commit(cr.codeIndex != index || cr.memory.Pointer() != pointer) {
+		return
+	}
+	cr.history.addCheckpoint(cr.codeIndex, cr.memory)
+
+	// Watchpoints stop after the operator, including the last one, replaying from a checkpoint never stops
+	if (ret != returnAfterExecuteOperator && ret != ReturnAfterFinish) || len(cr.watchPoint) == 0 || cr.history.rewinding {
+		return
+	}
+	entry, changes, _ := cr.lastStep()
+	if cr.watchReport = cr.firedWatches(entry, changes); cr.watchReport != "" {
+		// Breakpoint here is checked when continuing
+		cr.breakPointUsed = false
+		return ReturnReachWatch
+	}
+	return
+}
+
+// remember records the cell at pointer plus offset before it changes, only in debug mode.
+func (cr *CodeRunner) remember(offset int) {
+	if cr.debugFlag {
+		cr.rememberCell(offset)
+	}
+}
+
+// rememberCell records the cell at pointer plus offset in history.
+func (cr *CodeRunner) rememberCell(offset int) {
+	cr.history.remember(cr.memory.Pointer()+offset, cr.memory.Peek(offset))
+}
+
+// lastStep returns the entry and cell changes of the last executed operator, ok is false if there is none.
+func (cr *CodeRunner) lastStep() (entry historyEntry, changes []cellChange, ok bool) {
+	var h *history = cr.history
+	if h.steps == 0 {
+		return
+	}
+	if len(h.entries) == 0 {
+		cr.rebuildHistory()
+	}
+
+	entry = h.entries[len(h.entries)-1]
+	return entry, h.changes[entry.changes:], true
+}
+
+// undo restores the state before the last executed operator, returns false if there is none.
+func (cr *CodeRunner) undo() bool {
+	var h *history = cr.history
+	if h.steps == 0 {
+		return false
+	}
+	if len(h.entries) == 0 {
+		cr.rebuildHistory()
+	}
+
+	// Restore cells in reverse order, then pointer and operator index
+	var entry historyEntry = h.entries[len(h.entries)-1]
+	for i := len(h.changes) - 1; i >= entry.changes; i-- {
+		cr.memory.PokeAt(h.changes[i].address-cr.memory.Pointer(), h.changes[i].value)
+	}
+	if err := cr.memory.MovePtr(entry.pointer - cr.memory.Pointer()); err != nil {
+		panic("CodeRunner: unexpected error when undoing: " + err.Error())
+	}
+	cr.codeIndex = entry.index
+	h.changes = h.changes[:entry.changes]
+	h.entries = h.entries[:len(h.entries)-1]
+	h.steps--
+
+	// Give back logged input and multiply decision
+	switch cr.code.Operators[entry.index] {
+	case code.OpInput:
+		h.inputPos--
+
+	case code.OpMultiply:
+		if cr.memory.Peek(0) != 0 {
+			h.multiplyPos--
+		}
+	}
+	cr.uncountHits()
+	return true
+}
+
+// uncountHits takes back breakpoint hits counted after the current step,
+// hits counted at the current step are kept since continuing does not check them again.
+func (cr *CodeRunner) uncountHits() {
+	var h *history = cr.history
+	for len(h.hitLog) > 0 && h.hitLog[len(h.hitLog)-1].step > h.steps {
+		var last hitCount = h.hitLog[len(h.hitLog)-1]
+		h.hitLog = h.hitLog[:len(h.hitLog)-1]
+
+		// Deleted breakpoints have nothing to take back
+		var index int = cr.findBreakPoint(last.id)
+		if index == -1 {
+			continue
+		}
+		cr.breakPoint[index].hits--
+		if last.usedIgnore {
+			cr.breakPoint[index].ignore++
+		}
+	}
+}
+
+// rebuildHistory restores the last checkpoint before the current step and replays up to it,
+// so that steps dropped from history can be undone.
+func (cr *CodeRunner) rebuildHistory() {
+	var h *history = cr.history
+	var target int = h.steps
+
+	var last int = len(h.checkpoints) - 1
+	for h.checkpoints[last].step >= target {
+		last--
+	}
+	var restored checkpoint = h.checkpoints[last]
+	cr.memory = restored.memory.Clone()
+	cr.codeIndex = restored.index
+	h.steps = restored.step
+	h.inputPos = restored.inputPos
+	h.multiplyPos = restored.multiplyPos
+
+	// Replay without stopping, every step was executed before
+	h.rewinding = true
+	for h.steps < target {
+		var before int = h.steps
+		cr.execute()
+		if h.steps == before {
+			panic("CodeRunner: history replay diverged")
+		}
+	}
+	h.rewinding = false
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner_test
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// loopSource enters its loop 5 times, column 7 is the first operator inside it.
+const loopSource string = "+++++[>+<-]>."
+
+// newLoopRunner creates a debug runner of loopSource with a breakpoint inside the loop ignoring 2 crossings.
+func newLoopRunner(t *testing.T) (ret *coderunner.CodeRunner) {
+	c, err := codeanalyser.Analyse(loopSource, true, codeanalyser.O0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret = coderunner.New(c, true, coderunner.Options{Input: strings.NewReader(""), Output: io.Discard})
+	ret.AddColumnBreakPoint(1, 7, nil)
+	ret.IgnoreBreakPoint(1, 2)
+	return
+}
+
+// continueToEnd continues until the code finishes, collecting breakpoint reports of every stop.
+func continueToEnd(t *testing.T, cr *coderunner.CodeRunner) (ret []string) {
+	for {
+		switch code := cr.Continue(); code {
+		case coderunner.ReturnReachBreakPoint:
+			ret = append(ret, cr.BreakPointReport())
+
+		case coderunner.ReturnAfterFinish:
+			return
+
+		default:
+			t.Fatalf("unexpected return code %v", code)
+		}
+	}
+}
+
+// TestBreakPointHitsAcrossReverse checks that running backwards takes back hits and ignore counts,
+// so that running forward again stops as the first time.
+func TestBreakPointHitsAcrossReverse(t *testing.T) {
+	var expect []string = []string{
+		"Breakpoint 1 at line 1:7, hits 3\n",
+		"Breakpoint 1 at line 1:7, hits 4\n",
+		"Breakpoint 1 at line 1:7, hits 5\n",
+	}
+	if got := continueToEnd(t, newLoopRunner(t)); !slices.Equal(got, expect) {
+		t.Fatalf("forward stops are %q, expect %q", got, expect)
+	}
+
+	// Back from the first stop by every number of steps up to the beginning
+	for back := 1; back <= 40; back++ {
+		var cr *coderunner.CodeRunner = newLoopRunner(t)
+		if code := cr.Run(); code != coderunner.ReturnReachBreakPoint {
+			t.Fatalf("run returns %v, expect breakpoint", code)
+		}
+		cr.ReverseStep(back)
+		if got := continueToEnd(t, cr); !slices.Equal(got, expect) {
+			t.Errorf("stops after reverse-step %v are %q, expect %q", back, got, expect)
+		}
+	}
+
+	// Reverse continue stops at the previous hit, which keeps its count
+	var cr *coderunner.CodeRunner = newLoopRunner(t)
+	cr.Run()
+	cr.Continue()
+	if code := cr.ReverseContinue(); code != coderunner.ReturnReachBreakPoint {
+		t.Fatalf("reverse-continue returns %v, expect breakpoint", code)
+	}
+	if got := cr.BreakPointReport(); got != expect[0] {
+		t.Errorf("reverse-continue stops at %q, expect %q", got, expect[0])
+	}
+	if got := continueToEnd(t, cr); !slices.Equal(got, expect[1:]) {
+		t.Errorf("stops after reverse-continue are %q, expect %q", got, expect[1:])
+	}
+}
+
+// TestRuntimeErrorNotRecorded checks that an operator failing with a runtime error after remembering
+// its cell does not become a step, so reverse-step undoes the operator before it.
+func TestRuntimeErrorNotRecorded(t *testing.T) {
+	c, err := codeanalyser.Analyse(strings.Repeat("+", 256), true, codeanalyser.O0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options coderunner.Options = coderunner.Options{Output: io.Discard, Memory: memory.Config{Overflow: memory.OverflowTrap}}
+	var cr *coderunner.CodeRunner = coderunner.New(c, true, options)
+	if code := cr.Run(); code != coderunner.ReturnRuntimeError {
+		t.Fatalf("run returns %v, expect runtime error", code)
+	}
+
+	if code := cr.ReverseStep(1); code != coderunner.ReturnAfterStep {
+		t.Fatalf("reverse-step returns %v", code)
+	}
+	if got := cr.PeekCells(0, 1)[0]; got != 254 {
+		t.Errorf("cell is %v after reverse-step, expect 254", got)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner_test
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// scanCase fills cells from address 0 in the given direction, returns to address 0 and scans back over them.
+type scanCase struct {
+	name   string
+	source string
+	memory memory.Config
+	failed bool // Whether scanning stops with a runtime error
+}
+
+// fillAndScan sets count cells to 1 by stride without moving beyond the last one, moves back and scans
+// with the same stride, then writes a marker to the cell the scan stops at.
+func fillAndScan(count, stride int) string {
+	var forward, back string = ">", "<"
+	if stride < 0 {
+		forward, back = back, forward
+		stride = -stride
+	}
+	var step string = strings.Repeat(forward, stride)
+	return strings.Repeat("+"+step, count-1) + "+" + strings.Repeat(back, (count-1)*stride) +
+		"[" + step + "]" + strings.Repeat("+", 65) + "."
+}
+
+// TestScan runs scan loops at -O2 on CodeRunner and ThreadedRunner and compares them with the plain loops at -O0,
+// including scans growing the tape beyond its initial cells and scans stopped by tape limits.
+func TestScan(t *testing.T) {
+	var cases = []scanCase{
+		{name: "right", source: fillAndScan(10, 1)},
+		{name: "left", source: fillAndScan(10, -1)},
+		{name: "right by 3", source: fillAndScan(10, 3)},
+		{name: "left by 2", source: fillAndScan(10, -2)},
+		{name: "zero cell", source: "[>]+."},
+		{name: "long right", source: fillAndScan(5000, 1)},
+		{name: "long left", source: fillAndScan(5000, -1)},
+
+		// A new tape has cells from address -512 to 511, scans beyond them grow it
+		{name: "grow right", source: fillAndScan(512, 1)},
+		{name: "grow left", source: fillAndScan(513, -1)},
+		{name: "grow right by 3", source: fillAndScan(171, 3)},
+		{name: "grow left by 2", source: fillAndScan(257, -2)},
+		{name: "tape end", source: "+" + strings.Repeat(">+", 99) + strings.Repeat("<", 99) + ".[>]",
+			memory: memory.Config{TapeSize: 100}, failed: true},
+		{name: "tape end by 3", source: "+" + strings.Repeat(">+", 99) + strings.Repeat("<", 99) + ".[>>>]",
+			memory: memory.Config{TapeSize: 100}, failed: true},
+		{name: "left edge", source: "+" + strings.Repeat(">+", 9) + ".[<]",
+			memory: memory.Config{NoNegative: true}, failed: true},
+		{name: "max cells", source: "+" + strings.Repeat(">+", 9) + strings.Repeat("<", 9) + ".[>]",
+			memory: memory.Config{MaxCells: 10}, failed: true},
+		{name: "inside limit", source: fillAndScan(20, 1), memory: memory.Config{TapeSize: 100}},
+	}
+	for _, each := range cases {
+		var scan testprograms.Program = testprograms.Program{Name: each.name, Source: each.source}
+		var options coderunner.Options = coderunner.Options{Memory: each.memory}
+		expect, expectMessage := runEngine(t, scan, codeanalyser.O0, options, newCodeRunner)
+		expectMessage, _, _ = strings.Cut(expectMessage, " at operator")
+		if (expectMessage != "") != each.failed {
+			t.Fatalf("%v: loop at -O0 returned error %q", each.name, expectMessage)
+		}
+
+		if pointer, expectPointer := scanPointer(t, each, codeanalyser.O2), scanPointer(t, each, codeanalyser.O0); pointer != expectPointer {
+			t.Errorf("%v: CodeRunner stops at address %v, expect %v", each.name, pointer, expectPointer)
+		}
+
+		for _, engine := range []struct {
+			name   string
+			create func(c *code.Code, options coderunner.Options) coderunner.Engine
+		}{
+			{"CodeRunner", newCodeRunner},
+			{"ThreadedRunner", newThreaded},
+		} {
+			// Operator index differs between levels
+			got, message := runEngine(t, scan, codeanalyser.O2, options, engine.create)
+			message, _, _ = strings.Cut(message, " at operator")
+			if got != expect || message != expectMessage {
+				t.Errorf("%v: %v wrote %q (%q), expect %q (%q)", each.name, engine.name, got, message, expect, expectMessage)
+			}
+		}
+	}
+}
+
+// scanPointer runs the case on CodeRunner at the given level and returns the pointer where it ends,
+// failing if -O2 does not use a scan operator.
+func scanPointer(t *testing.T, each scanCase, level codeanalyser.OptLevel) int {
+	t.Helper()
+	c, err := codeanalyser.Analyse(each.source, false, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level == codeanalyser.O2 && !slices.Contains(c.Operators, code.OpScan) {
+		t.Errorf("%v: no scan operator at -O2", each.name)
+	}
+
+	var cr *coderunner.CodeRunner = coderunner.New(c, false, coderunner.Options{Output: io.Discard, Memory: each.memory})
+	cr.Run()
+	return cr.GetMemoryPointer()
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner
+
+import (
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/runtimeError"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// handler executes one operator and returns the index of the next operator to execute.
+type handler func() (next int)
+
+// ThreadedRunner runs code without debugging like CodeRunner, but each operator is compiled
+// in advance to a handler with its auxiliary data and jump target, so running is just calling
+// handlers one after another.
+type ThreadedRunner struct {
+	runner   *CodeRunner // Holds memory, streams and options
+	handlers []handler
+	failed   int // Returned by handlers after a runtime error, beyond any operator
+}
+
+// NewThreaded compiles code to handlers, options are used like New does.
+func NewThreaded(code *code.Code, options Options) (ret *ThreadedRunner) {
+	ret = &ThreadedRunner{
+		runner:   New(code, false, options),
+		handlers: make([]handler, code.CodeCount),
+		failed:   code.CodeCount + 1,
+	}
+	for index := range code.CodeCount {
+		ret.handlers[index] = ret.compile(index)
+	}
+	return
+}
+
+// Run runs the code from the beginning.
+func (tr *ThreadedRunner) Run() (ret ReturnCode) {
+	tr.runner.Reset()
+	defer tr.runner.output.Flush()
+
+	var handlers []handler = tr.handlers
+	for index := 0; index < len(handlers); {
+		index = handlers[index]()
+	}
+	if tr.runner.runtimeErr != nil {
+		return ReturnRuntimeError
+	}
+	return ReturnAfterFinish
+}
+
+// RuntimeError returns the error of the last run, which is nil if no error occurred.
+func (tr *ThreadedRunner) RuntimeError() error {
+	return tr.runner.runtimeErr
+}
+
+// compile returns the handler of the operator at index.
+//
+// Memory is reached through the runner every time, since Run replaces it.
+func (tr *ThreadedRunner) compile(index int) handler {
+	var cr *CodeRunner = tr.runner
+	var c *code.Code = cr.code
+	var auxiliary uint64 = c.Auxiliary[index]
+	var offset int = c.Offsets[index]
+	var next int = index + 1
+	var wrap bool = cr.memoryConfig.Overflow == memory.OverflowWrap
+
+	// raise records a runtime error of this operator and stops running
+	var raise = func(err error) int {
+		cr.runtimeErr = runtimeError.New(index, c.SourceLines[index], err)
+		return tr.failed
+	}
+
+	switch c.Operators[index] {
+	case code.OpAdd:
+		if offset == 0 {
+			return func() int {
+				if err := cr.memory.Add(auxiliary); err != nil {
+					return raise(err)
+				}
+				return next
+			}
+		}
+		return func() int {
+			if err := cr.memory.Extend(offset, offset); err != nil {
+				return raise(err)
+			}
+			if err := cr.memory.AddAt(offset, auxiliary); err != nil {
+				return raise(err)
+			}
+			return next
+		}
+
+	case code.OpSub:
+		if offset == 0 {
+			return func() int {
+				if err := cr.memory.Sub(auxiliary); err != nil {
+					return raise(err)
+				}
+				return next
+			}
+		}
+		return func() int {
+			if err := cr.memory.Extend(offset, offset); err != nil {
+				return raise(err)
+			}
+			if err := cr.memory.SubAt(offset, auxiliary); err != nil {
+				return raise(err)
+			}
+			return next
+		}
+
+	case code.OpMoveLeft, code.OpMoveRight:
+		var steps int = int(auxiliary)
+		if c.Operators[index] == code.OpMoveLeft {
+			steps = -steps
+		}
+		return func() int {
+			if err := cr.memory.MovePtr(steps); err != nil {
+				return raise(err)
+			}
+			return next
+		}
+
+	case code.OpLeftBracket:
+		var loopEnd int = int(auxiliary)
+		return func() int {
+			if cr.memory.Peek(0) == 0 {
+				return loopEnd
+			}
+			return next
+		}
+
+	case code.OpRightBracket:
+		var loopBody int = int(auxiliary)
+		if loopBody == index {
+			// Empty loop never ends once entered, warn like the interpreter
+			return func() int {
+				if cr.memory.Peek(0) == 0 {
+					return next
+				}
+				if !cr.infiniteLoopWarned {
+					cr.warn("Infinite loop at operator %v", index)
+					cr.infiniteLoopWarned = true
+				}
+				return loopBody
+			}
+		}
+		return func() int {
+			if cr.memory.Peek(0) != 0 {
+				return loopBody
+			}
+			return next
+		}
+
+	case code.OpInput:
+		return func() int {
+			// Flush output before waiting for input
+			cr.output.Flush()
+			cr.readInput()
+			return next
+		}
+
+	case code.OpOutput:
+		return func() int {
+			// Only the lowest byte is written for wide cells
+			cr.output.WriteByte(byte(cr.memory.Peek(0)))
+			return next
+		}
+
+	case code.OpClear, code.OpSet:
+		var value uint64 = 0
+		if c.Operators[index] == code.OpSet {
+			value = auxiliary
+		} else if auxiliary != 0 && !wrap {
+			// [+] without wrapping runs one iteration at a time, see clearCell
+			return func() int {
+				if err := cr.memory.Extend(offset, offset); err != nil {
+					return raise(err)
+				}
+				if cr.memory.Peek(offset) == 0 {
+					return next
+				}
+				if err := cr.memory.AddAt(offset, 1); err != nil {
+					return raise(err)
+				}
+				return index
+			}
+		}
+		if offset == 0 && value == 0 {
+			return func() int {
+				cr.memory.Poke(0)
+				return next
+			}
+		}
+		return func() int {
+			if err := cr.memory.Extend(offset, offset); err != nil {
+				return raise(err)
+			}
+			cr.memory.PokeAt(offset, 0)
+			if value == 0 {
+				return next
+			}
+			if err := cr.memory.AddAt(offset, value); err != nil {
+				return raise(err)
+			}
+			return next
+		}
+
+	case code.OpScan:
+		var stride int = int(int64(auxiliary))
+		return func() int {
+			if err := cr.memory.Scan(stride); err != nil {
+				return raise(err)
+			}
+			return next
+		}
+
+	case code.OpMultiply:
+		// multiply skips the loop by moving code index, otherwise the loop runs
+		return func() int {
+			cr.codeIndex = next
+			cr.multiply(auxiliary)
+			return cr.codeIndex
+		}
+	}
+	panic("ThreadedRunner: unknown operator")
+}
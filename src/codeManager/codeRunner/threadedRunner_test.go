@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// runEngine runs the program on the engine made by create, returning its output and runtime error message.
+func runEngine(t testing.TB, each testprograms.Program, level codeanalyser.OptLevel, options coderunner.Options,
+	create func(c *code.Code, options coderunner.Options) coderunner.Engine) (output string, message string) {
+	t.Helper()
+	c, err := codeanalyser.Analyse(each.Source, false, level)
+	if err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+
+	var buffer bytes.Buffer
+	options.Input, options.Output = strings.NewReader(each.Input), &buffer
+	var engine coderunner.Engine = create(c, options)
+	if engine.Run() == coderunner.ReturnRuntimeError {
+		message = engine.RuntimeError().Error()
+	}
+	return buffer.String(), message
+}
+
+func newCodeRunner(c *code.Code, options coderunner.Options) coderunner.Engine {
+	return coderunner.New(c, false, options)
+}
+
+func newThreaded(c *code.Code, options coderunner.Options) coderunner.Engine {
+	return coderunner.NewThreaded(c, options)
+}
+
+// TestThreadedSameOutput runs every program on ThreadedRunner and CodeRunner, including runtime errors
+// of trapping cells and a short tape.
+func TestThreadedSameOutput(t *testing.T) {
+	// Wide cells only with -O2, which clears them without counting to the maximum
+	var configs = []struct {
+		level  codeanalyser.OptLevel
+		memory memory.Config
+	}{
+		{codeanalyser.O0, memory.Config{}},
+		{codeanalyser.O2, memory.Config{}},
+		{codeanalyser.O2, memory.Config{CellWidth: memory.CellWidth16}},
+		{codeanalyser.O2, memory.Config{CellWidth: memory.CellWidth32}},
+		{codeanalyser.O2, memory.Config{Overflow: memory.OverflowTrap}},
+		{codeanalyser.O2, memory.Config{TapeSize: 8}},
+	}
+	for _, each := range testprograms.Load(t) {
+		for _, config := range configs {
+			var options coderunner.Options = coderunner.Options{Memory: config.memory}
+			expect, expectMessage := runEngine(t, each, config.level, options, newCodeRunner)
+			got, message := runEngine(t, each, config.level, options, newThreaded)
+			if got != expect || message != expectMessage {
+				t.Errorf("%v -O%v %+v: ThreadedRunner wrote %q (%q), CodeRunner wrote %q (%q)",
+					each.Name, config.level, config.memory, got, message, expect, expectMessage)
+			}
+			if config.memory == (memory.Config{}) && got != each.Output {
+				t.Errorf("%v -O%v: ThreadedRunner wrote %q, expect %q", each.Name, config.level, got, each.Output)
+			}
+		}
+	}
+}
+
+// TestThreadedEOFPolicies reads beyond the end of input with every EOF policy.
+func TestThreadedEOFPolicies(t *testing.T) {
+	var eof testprograms.Program = testprograms.Program{Name: "eof", Source: ",.,.,.+.", Input: "A"}
+	for _, policy := range []coderunner.EOFPolicy{coderunner.EOFZero, coderunner.EOFUnchanged, coderunner.EOFMinusOne} {
+		var options coderunner.Options = coderunner.Options{EOF: policy}
+		expect, _ := runEngine(t, eof, codeanalyser.O2, options, newCodeRunner)
+		if got, _ := runEngine(t, eof, codeanalyser.O2, options, newThreaded); got != expect {
+			t.Errorf("EOF policy %v: ThreadedRunner wrote %q, CodeRunner wrote %q", policy, got, expect)
+		}
+	}
+}
+
+// engines are the engines compared by benchmarks.
+var engines = []struct {
+	name   string
+	create func(c *code.Code, options coderunner.Options) coderunner.Engine
+}{
+	{"CodeRunner", newCodeRunner},
+	{"ThreadedRunner", newThreaded},
+}
+
+// BenchmarkEngines runs all test programs at -O2 on each engine, which mostly measures starting a run.
+func BenchmarkEngines(b *testing.B) {
+	benchmarkPrograms(b, testprograms.Load(b))
+}
+
+// BenchmarkBench runs the classic bench program at -O2 on each engine, which mostly measures running operators.
+func BenchmarkBench(b *testing.B) {
+	benchmarkPrograms(b, []testprograms.Program{testprograms.Bench(b, "bench")})
+}
+
+// benchmarkPrograms runs the programs one after another on each engine, checking their output.
+func benchmarkPrograms(b *testing.B, programs []testprograms.Program) {
+	var codes []*code.Code
+	for _, each := range programs {
+		c, err := codeanalyser.Analyse(each.Source, false, codeanalyser.O2)
+		if err != nil {
+			b.Fatal(err)
+		}
+		codes = append(codes, c)
+	}
+
+	for _, engine := range engines {
+		b.Run(engine.name, func(b *testing.B) {
+			// Only running is measured, engines are created again since input is buffered
+			var runs []coderunner.Engine = make([]coderunner.Engine, len(programs))
+			var outputs []*bytes.Buffer = make([]*bytes.Buffer, len(programs))
+			for b.Loop() {
+				b.StopTimer()
+				for index, each := range programs {
+					outputs[index] = new(bytes.Buffer)
+					var options coderunner.Options = coderunner.Options{Input: strings.NewReader(each.Input), Output: outputs[index]}
+					runs[index] = engine.create(codes[index], options)
+				}
+				b.StartTimer()
+				for _, run := range runs {
+					run.Run()
+				}
+			}
+
+			for index, each := range programs {
+				if outputs[index].String() != each.Output {
+					b.Errorf("%v: wrote %q, expect %q", each.Name, outputs[index].String(), each.Output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package coderunner
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+)
+
+type WatchKind byte
+
+// Watch kinds decide which accesses to the watched cell stop running, value is only used by the last three.
+const (
+	WatchWrite  = iota // Any operator writing the cell
+	WatchRead          // Loop tests and output reading the cell
+	WatchAccess        // Reading or writing
+	WatchTo            // Value changes to value
+	WatchFrom          // Value changes from value
+	WatchCross         // Value crosses value, from below it to at or above it or back
+)
+
+func (kind WatchKind) String() string {
+	switch kind {
+	case WatchWrite:
+		return "write"
+
+	case WatchRead:
+		return "read"
+
+	case WatchAccess:
+		return "access"
+
+	case WatchTo:
+		return "to"
+
+	case WatchFrom:
+		return "from"
+
+	case WatchCross:
+		return "cross"
+	}
+	return "invalid"
+}
+
+// watchPoint stops running after an operator accesses the cell at address in the way of its kind.
+type watchPoint struct {
+	address int
+	kind    WatchKind
+	value   int64
+}
+
+// describe returns the kind with its value, like "write", "change to 10" or "cross 128".
+func (wp *watchPoint) describe() string {
+	switch wp.kind {
+	case WatchTo, WatchFrom:
+		return fmt.Sprintf("change %v %v", wp.kind, wp.value)
+
+	case WatchCross:
+		return fmt.Sprintf("cross %v", wp.value)
+	}
+	return wp.kind.String()
+}
+
+// compare orders watchpoints by address, then by kind and value.
+func (wp *watchPoint) compare(other *watchPoint) int {
+	return cmp.Or(cmp.Compare(wp.address, other.address), cmp.Compare(wp.kind, other.kind), cmp.Compare(wp.value, other.value))
+}
+
+// AddWatch sets a watchpoint on the memory cell at address, value is ignored unless kind is
+// WatchTo, WatchFrom or WatchCross.
+func (cr *CodeRunner) AddWatch(address int, kind WatchKind, value int64) (message string) {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't add watchpoint when not in debug mode")
+	}
+	if kind < WatchTo {
+		value = 0
+	}
+
+	var added watchPoint = watchPoint{address: address, kind: kind, value: value}
+	position, found := slices.BinarySearchFunc(cr.watchPoint, &added, func(each watchPoint, target *watchPoint) int {
+		return each.compare(target)
+	})
+	if found {
+		message = fmt.Sprintf("Warning: Address %v is already being watched on %v\n\n", address, added.describe())
+	} else {
+		cr.watchPoint = slices.Insert(cr.watchPoint, position, added)
+		message = fmt.Sprintf("Watching memory %v on %v\n\n", address, added.describe())
+	}
+	return
+}
+
+func (cr *CodeRunner) RemoveWatch(index int) (message string) {
+	if index <= 0 || index > len(cr.watchPoint) {
+		message = fmt.Sprintf("Error: Watchpoint index out of range, get %v, watchpoint count is %v\n\n", index, len(cr.watchPoint))
+		return
+	}
+
+	message = fmt.Sprintf("Watchpoint %v at address %v removed\n\n", index, cr.watchPoint[index-1].address)
+	cr.watchPoint = slices.Delete(cr.watchPoint, index-1, index)
+	return
+}
+
+func (cr *CodeRunner) ClearWatches() {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't clear watchpoints when not in debug mode")
+	}
+
+	cr.watchPoint = make([]watchPoint, 0)
+}
+
+// PrintWatchInfo prints all watchpoints information.
+func (cr *CodeRunner) PrintWatchInfo() {
+	if !cr.debugFlag {
+		panic("CodeRunner: can't print watchpoints when not in debug mode")
+	}
+
+	// Print watch info
+	if len(cr.watchPoint) == 0 {
+		fmt.Print("No watchpoints exist now.\n\n")
+	} else {
+		// Print each watchpoint
+		fmt.Println("Watchpoints:")
+		fmt.Printf("%-6v%-10v%v\n", "Num", "Address", "Kind")
+		for index, each := range cr.watchPoint {
+			fmt.Printf("%-6v%-10v%v\n", index+1, each.address, each.describe())
+		}
+		fmt.Print("\n")
+	}
+}
+
+// WatchReport returns the watchpoints fired for the last ReturnReachWatch with their values, one per line.
+func (cr *CodeRunner) WatchReport() string {
+	return cr.watchReport
+}
+
+// firedWatches describes the watchpoints fired by an executed operator, empty if none.
+//
+// The memory must be the state right after the operator, changes are the cells it wrote.
+func (cr *CodeRunner) firedWatches(entry historyEntry, changes []cellChange) string {
+	var report strings.Builder
+	for index := range cr.watchPoint {
+		var each *watchPoint = &cr.watchPoint[index]
+		var current uint32 = cr.memory.Peek(each.address - cr.memory.Pointer())
+
+		// First change holds the value before the operator
+		var old uint32 = current
+		var written bool = false
+		for _, change := range changes {
+			if change.address == each.address {
+				old, written = change.value, true
+				break
+			}
+		}
+
+		var fired bool
+		switch each.kind {
+		case WatchWrite:
+			fired = written
+
+		case WatchRead:
+			fired = cr.readsAddress(entry, each.address)
+
+		case WatchAccess:
+			fired = written || cr.readsAddress(entry, each.address)
+
+		case WatchTo:
+			fired = written && int64(old) != each.value && int64(current) == each.value
+
+		case WatchFrom:
+			fired = written && int64(old) == each.value && int64(current) != each.value
+
+		case WatchCross:
+			fired = written && (int64(old) < each.value) != (int64(current) < each.value)
+		}
+		if !fired {
+			continue
+		}
+
+		if written {
+			fmt.Fprintf(&report, "Watchpoint %v (%v) at address %v: old %v, new %v\n", index+1, each.describe(), each.address, old, current)
+		} else {
+			fmt.Fprintf(&report, "Watchpoint %v (%v) at address %v: value %v\n", index+1, each.describe(), each.address, current)
+		}
+	}
+	return report.String()
+}
+
+// readsAddress checks whether an executed operator tested or printed the cell at address.
+//
+// Loop tests include brackets and the loops replaced by clear, set, scan and multiply.
+func (cr *CodeRunner) readsAddress(entry historyEntry, address int) bool {
+	switch cr.code.Operators[entry.index] {
+	case code.OpLeftBracket, code.OpRightBracket, code.OpOutput:
+		return address == entry.pointer
+
+	case code.OpClear, code.OpSet:
+		return address == entry.pointer+cr.code.Offsets[entry.index]
+
+	case code.OpMultiply:
+		// Otherwise the loop runs and its brackets test the cell
+		return address == entry.pointer && cr.codeIndex != entry.index+1
+
+	case code.OpScan:
+		// Every cell passed is tested, pointer stops at the last one
+		var stride int = int(int64(cr.code.Auxiliary[entry.index]))
+		var passed int = address - entry.pointer
+		var moved int = cr.memory.Pointer() - entry.pointer
+		return passed%stride == 0 && passed/stride >= 0 && passed/stride <= moved/stride
+	}
+	return false
+}
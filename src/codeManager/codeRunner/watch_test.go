@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package coderunner
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+)
+
+// watchStops runs source with the watchpoints until it finishes, returning the operator each stop
+// fired on with the report of the stop.
+func watchStops(t *testing.T, source string, level codeanalyser.OptLevel, watches ...watchPoint) (ret []string) {
+	t.Helper()
+	c, err := codeanalyser.Analyse(source, true, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr *CodeRunner = New(c, true, Options{Input: strings.NewReader(""), Output: io.Discard})
+	for _, each := range watches {
+		cr.AddWatch(each.address, each.kind, each.value)
+	}
+	for {
+		switch stop := cr.Continue(); stop {
+		case ReturnReachWatch:
+			entry, _, _ := cr.lastStep()
+			ret = append(ret, fmt.Sprintf("%v %v: %v", entry.index, c.Operators[entry.index], strings.TrimSuffix(cr.WatchReport(), "\n")))
+
+		case ReturnAfterFinish:
+			return
+
+		default:
+			t.Fatalf("%q: unexpected return code %v", source, stop)
+		}
+	}
+}
+
+// TestWatchKinds checks which operators stop at each kind of watchpoint and the values reported.
+func TestWatchKinds(t *testing.T) {
+	var cases = []struct {
+		name    string
+		source  string
+		level   codeanalyser.OptLevel
+		watches []watchPoint
+		expect  []string
+	}{
+		{"write", "+>+<-", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchWrite}}, []string{
+			"0 Add: Watchpoint 1 (write) at address 0: old 0, new 1",
+			"4 Sub: Watchpoint 1 (write) at address 0: old 1, new 0",
+		}},
+		{"read", "+[-].", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchRead}}, []string{
+			"1 LeftBracket: Watchpoint 1 (read) at address 0: value 1",
+			"3 RightBracket: Watchpoint 1 (read) at address 0: value 0",
+			"4 Output: Watchpoint 1 (read) at address 0: value 0",
+		}},
+		{"access", "+>.<.", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchAccess}}, []string{
+			"0 Add: Watchpoint 1 (access) at address 0: old 0, new 1",
+			"4 Output: Watchpoint 1 (access) at address 0: value 1",
+		}},
+		{"to", "+++--", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchTo, value: 2}}, []string{
+			"1 Add: Watchpoint 1 (change to 2) at address 0: old 1, new 2",
+			"3 Sub: Watchpoint 1 (change to 2) at address 0: old 3, new 2",
+		}},
+		{"from", "+++--", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchFrom, value: 2}}, []string{
+			"2 Add: Watchpoint 1 (change from 2) at address 0: old 2, new 3",
+			"4 Sub: Watchpoint 1 (change from 2) at address 0: old 2, new 1",
+		}},
+		{"cross", "+++--", codeanalyser.O0, []watchPoint{{address: 0, kind: WatchCross, value: 2}}, []string{
+			"1 Add: Watchpoint 1 (cross 2) at address 0: old 1, new 2",
+			"4 Sub: Watchpoint 1 (cross 2) at address 0: old 2, new 1",
+		}},
+		{"merged", "+++>", codeanalyser.O1, []watchPoint{{address: 0, kind: WatchTo, value: 2}, {address: 0, kind: WatchCross, value: 2}}, []string{
+			"0 Add: Watchpoint 2 (cross 2) at address 0: old 0, new 3",
+		}},
+		{"scan right", "+>>+>>>+<<<<<[>>]", codeanalyser.O2, []watchPoint{
+			{address: 1, kind: WatchRead}, {address: 2, kind: WatchRead}, {address: 2, kind: WatchWrite},
+			{address: 4, kind: WatchRead}, {address: 5, kind: WatchRead}, {address: 6, kind: WatchRead},
+		}, []string{
+			"1 Add: Watchpoint 2 (write) at address 2: old 0, new 1",
+			"3 Scan: Watchpoint 3 (read) at address 2: value 1\nWatchpoint 4 (read) at address 4: value 0",
+		}},
+		{"scan left", "+<<+<<+>>>>[<<]", codeanalyser.O2, []watchPoint{
+			{address: 2, kind: WatchRead}, {address: -3, kind: WatchRead}, {address: -4, kind: WatchRead},
+			{address: -6, kind: WatchRead}, {address: -8, kind: WatchRead},
+		}, []string{
+			"3 Scan: Watchpoint 2 (read) at address -6: value 0\nWatchpoint 3 (read) at address -4: value 1",
+		}},
+		{"multiply watched", "++[>+++<-]", codeanalyser.O2, []watchPoint{{address: 0, kind: WatchRead}}, []string{
+			"2 LeftBracket: Watchpoint 1 (read) at address 0: value 2",
+			"5 RightBracket: Watchpoint 1 (read) at address 0: value 1",
+			"5 RightBracket: Watchpoint 1 (read) at address 0: value 0",
+		}},
+	}
+	for _, each := range cases {
+		if got := watchStops(t, each.source, each.level, each.watches...); !slices.Equal(got, each.expect) {
+			t.Errorf("%v: stops are %q, expect %q", each.name, got, each.expect)
+		}
+	}
+}
+
+// TestWatchMultiplyRead checks that a multiply run in one step reads its loop cell. Watching a cell of the
+// loop runs the loop instead, so watches are added after running and found running backwards.
+func TestWatchMultiplyRead(t *testing.T) {
+	c, err := codeanalyser.Analyse("++[>+++<-]>.", true, codeanalyser.O2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr *CodeRunner = New(c, true, Options{Input: strings.NewReader(""), Output: io.Discard})
+	if ret := cr.Run(); ret != ReturnAfterFinish {
+		t.Fatalf("run returns %v, expect finish", ret)
+	}
+	cr.AddWatch(0, WatchRead, 0)
+	cr.AddWatch(1, WatchWrite, 0)
+
+	if ret := cr.ReverseContinue(); ret != ReturnReachWatch {
+		t.Fatalf("reverse continue returns %v, expect watch", ret)
+	}
+	entry, _, _ := cr.lastStep()
+	var expect string = "Watchpoint 1 (read) at address 0: old 2, new 0\nWatchpoint 2 (write) at address 1: old 0, new 6\n"
+	if c.Operators[entry.index] != code.OpMultiply || cr.WatchReport() != expect {
+		t.Errorf("stopped after %v with %q, expect multiply with %q", c.Operators[entry.index], cr.WatchReport(), expect)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package expression parses and evaluates conditions of breakpoints like "cell == 10 && ptr > 3".
+//
+// Values are int64, comparisons and logical operators give 1 or 0, and any non-zero value is true.
+// Operators and their precedence follow C:
+//
+//	! - +        unary
+//	* / %
+//	+ -
+//	< <= > >=
+//	== !=
+//	&&
+//	||
+//
+// Operands are decimal numbers, character literals like 'A', parentheses and names:
+//
+//	cell         current cell
+//	cell[expr]   cell at current pointer plus expr
+//	mem[expr]    cell at absolute address expr
+//	ptr          memory pointer
+//	hits         times the breakpoint has been reached, including this time
+package expression
+
+import "errors"
+
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Env provides values of names when evaluating an expression.
+type Env struct {
+	Pointer int
+	Peek    func(address int) uint32 // Cell at absolute address
+	Hits    int64
+}
+
+// Expr is a parsed expression.
+type Expr struct {
+	text string
+	root node
+}
+
+// String returns the text the expression was parsed from.
+func (e *Expr) String() string {
+	return e.text
+}
+
+// Eval evaluates the expression, only returns ErrDivisionByZero as error.
+func (e *Expr) Eval(env *Env) (ret int64, err error) {
+	return e.root.eval(env)
+}
+
+// IsTrue evaluates the expression and reports whether its value is non-zero.
+func (e *Expr) IsTrue(env *Env) (ret bool, err error) {
+	value, err := e.root.eval(env)
+	return value != 0, err
+}
+
+type node interface {
+	eval(env *Env) (int64, error)
+}
+
+// number is a constant.
+type number int64
+
+func (n number) eval(env *Env) (int64, error) {
+	return int64(n), nil
+}
+
+// variable is cell, ptr or hits without brackets.
+type variable string
+
+func (n variable) eval(env *Env) (int64, error) {
+	switch n {
+	case "cell":
+		return int64(env.Peek(env.Pointer)), nil
+
+	case "ptr":
+		return int64(env.Pointer), nil
+
+	case "hits":
+		return env.Hits, nil
+	}
+	panic("Expression: unknown variable " + string(n))
+}
+
+// index is cell[expr] or mem[expr].
+type index struct {
+	relative bool // cell[expr] is relative to pointer
+	address  node
+}
+
+func (n *index) eval(env *Env) (ret int64, err error) {
+	address, err := n.address.eval(env)
+	if err != nil {
+		return
+	}
+	if n.relative {
+		address += int64(env.Pointer)
+	}
+	return int64(env.Peek(int(address))), nil
+}
+
+// unary is an operator with one operand.
+type unary struct {
+	operator string
+	operand  node
+}
+
+func (n *unary) eval(env *Env) (ret int64, err error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return
+	}
+
+	switch n.operator {
+	case "-":
+		return -value, nil
+
+	case "+":
+		return value, nil
+
+	case "!":
+		return boolValue(value == 0), nil
+	}
+	panic("Expression: unknown unary operator " + n.operator)
+}
+
+// binary is an operator with two operands, && and || only evaluate the right one when needed.
+type binary struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n *binary) eval(env *Env) (ret int64, err error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return
+	}
+
+	// Short circuit
+	if n.operator == "&&" && left == 0 {
+		return 0, nil
+	}
+	if n.operator == "||" && left != 0 {
+		return 1, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return
+	}
+
+	switch n.operator {
+	case "&&", "||":
+		return boolValue(right != 0), nil
+
+	case "==":
+		return boolValue(left == right), nil
+
+	case "!=":
+		return boolValue(left != right), nil
+
+	case "<":
+		return boolValue(left < right), nil
+
+	case "<=":
+		return boolValue(left <= right), nil
+
+	case ">":
+		return boolValue(left > right), nil
+
+	case ">=":
+		return boolValue(left >= right), nil
+
+	case "+":
+		return left + right, nil
+
+	case "-":
+		return left - right, nil
+
+	case "*":
+		return left * right, nil
+
+	case "/", "%":
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		if n.operator == "/" {
+			return left / right, nil
+		}
+		return left % right, nil
+	}
+	panic("Expression: unknown binary operator " + n.operator)
+}
+
+// boolValue converts bool to 1 or 0.
+func boolValue(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
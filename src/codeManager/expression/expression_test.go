@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package expression_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/expression"
+)
+
+// env has the pointer at address 5, cells 3, 5 and 8 are set and others are 0.
+var env = &expression.Env{
+	Pointer: 5,
+	Peek: func(address int) uint32 {
+		return map[int]uint32{3: 7, 5: 42, 8: 'A'}[address]
+	},
+	Hits: 3,
+}
+
+func TestEval(t *testing.T) {
+	var cases = []struct {
+		text   string
+		expect int64
+	}{
+		// Precedence and associativity
+		{"1-2-3", -4},
+		{"2+3*4", 14},
+		{"(2+3)*4", 20},
+		{"20/2/5", 2},
+		{"7%4*2", 6},
+		{"-7/2", -3},
+		{"-7%3", -1},
+		{"-2*-3", 6},
+		{"!0&&1||0", 1},
+		{"!1||0&&1", 0},
+		{"1||0&&0", 1},
+		{"1<2==1", 1},
+		{"3>2>1", 0},
+		{"!!5", 1},
+
+		// Names
+		{"ptr", 5},
+		{"hits", 3},
+		{"cell", 42},
+		{"cell[0]", 42},
+		{"cell[-2]", 7},
+		{"cell[1+2]", 'A'},
+		{"cell[-6]", 0},
+		{"mem[3]", 7},
+		{"mem[ptr+3]", 'A'},
+		{"mem[cell[-2]-4]", 7},
+
+		// Character literals
+		{"'A'", 65},
+		{"' '", 32},
+		{"'0'+1", '1'},
+		{"cell[3]=='A'", 1},
+
+		// Short circuit skips the right operand
+		{"0&&1/0", 0},
+		{"1||1%0", 1},
+		{"0&&mem[1/0]||1", 1},
+	}
+	for _, each := range cases {
+		expr, err := expression.Parse(each.text)
+		if err != nil {
+			t.Errorf("%q: %v", each.text, err)
+			continue
+		}
+		value, err := expr.Eval(env)
+		if err != nil || value != each.expect {
+			t.Errorf("%q: evaluated to %v, %v, expect %v", each.text, value, err, each.expect)
+		}
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	for _, text := range []string{"1/0", "1%0", "1&&1/0", "0||1%(ptr-5)", "cell[1/0]"} {
+		expr, err := expression.Parse(text)
+		if err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+		if _, err = expr.Eval(env); !errors.Is(err, expression.ErrDivisionByZero) {
+			t.Errorf("%q: error %v, expect division by zero", text, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var cases = []struct {
+		text   string
+		expect string
+	}{
+		{"foo == 1", `Error: unknown name "foo" at column 1, expect cell, mem, ptr or hits`},
+		{"cell == bar", `Error: unknown name "bar" at column 9, expect cell, mem, ptr or hits`},
+		{"cell == 'A", "Error: unclosed character literal at column 9"},
+		{"'AB'", "Error: unclosed character literal at column 1"},
+		{"cell ==", "Error: unexpected end of expression at column 8"},
+		{"1 + * 2", `Error: unexpected "*" at column 5`},
+		{"(1 + 2", "Error: unexpected end of expression at column 7"},
+		{"mem 3", `Error: unexpected "3" at column 5`},
+		{"cell[1", "Error: unexpected end of expression at column 7"},
+		{"1 2", `Error: unexpected "2" at column 3`},
+		{"ptr # 1", `Error: unexpected character '#' at column 5`},
+		{"99999999999999999999", "Error: number 99999999999999999999 at column 1 is too large"},
+	}
+	for _, each := range cases {
+		_, err := expression.Parse(each.text)
+		if err == nil || err.Error() != each.expect {
+			t.Errorf("%q: error %v, expect %q", each.text, err, each.expect)
+		}
+	}
+}
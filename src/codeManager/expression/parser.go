@@ -0,0 +1,262 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package expression
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind byte
+
+const (
+	tokenEnd = iota
+	tokenNumber
+	tokenName
+	tokenOperator // Also parentheses and brackets
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	value  int64 // Value of number
+	column int   // Start from 1, used for error messages
+}
+
+// Operators of each precedence level, from lowest to highest.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// Operators tried in order, longer ones first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]"}
+
+// Parse parses text into an expression, see package documentation for syntax.
+func Parse(text string) (ret *Expr, err error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return
+	}
+
+	var p parser = parser{tokens: tokens}
+	root, err := p.parseBinary(0)
+	if err != nil {
+		return
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, p.unexpected()
+	}
+
+	ret = &Expr{text: strings.TrimSpace(text), root: root}
+	return
+}
+
+// tokenize splits text into tokens, ending with a tokenEnd.
+func tokenize(text string) (ret []token, err error) {
+	var runes []rune = []rune(text)
+	var position int = 0
+	for {
+		// Skip spaces
+		for position < len(runes) && unicode.IsSpace(runes[position]) {
+			position++
+		}
+		if position == len(runes) {
+			ret = append(ret, token{kind: tokenEnd, column: position + 1})
+			return
+		}
+
+		var begin int = position
+		var char rune = runes[position]
+		switch {
+		case unicode.IsDigit(char):
+			for position < len(runes) && unicode.IsDigit(runes[position]) {
+				position++
+			}
+			value, parseErr := strconv.ParseInt(string(runes[begin:position]), 10, 64)
+			if parseErr != nil {
+				return nil, fmt.Errorf("Error: number %v at column %v is too large", string(runes[begin:position]), begin+1)
+			}
+			ret = append(ret, token{kind: tokenNumber, text: string(runes[begin:position]), value: value, column: begin + 1})
+
+		case unicode.IsLetter(char):
+			for position < len(runes) && unicode.IsLetter(runes[position]) {
+				position++
+			}
+			ret = append(ret, token{kind: tokenName, text: string(runes[begin:position]), column: begin + 1})
+
+		case char == '\'':
+			// Character literal like 'A'
+			if position+2 >= len(runes) || runes[position+2] != '\'' {
+				return nil, fmt.Errorf("Error: unclosed character literal at column %v", begin+1)
+			}
+			position += 3
+			ret = append(ret, token{kind: tokenNumber, text: string(runes[begin:position]), value: int64(runes[begin+1]), column: begin + 1})
+
+		default:
+			var rest string = string(runes[position:])
+			var index int = slices.IndexFunc(operators, func(operator string) bool {
+				return strings.HasPrefix(rest, operator)
+			})
+			if index == -1 {
+				return nil, fmt.Errorf("Error: unexpected character %q at column %v", char, begin+1)
+			}
+			position += len(operators[index])
+			ret = append(ret, token{kind: tokenOperator, text: operators[index], column: begin + 1})
+		}
+	}
+}
+
+// parser is a recursive descent parser over tokens.
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() (ret token) {
+	ret = p.tokens[p.position]
+	if ret.kind != tokenEnd {
+		p.position++
+	}
+	return
+}
+
+// accept consumes the next token if it is the given operator.
+func (p *parser) accept(operator string) bool {
+	if p.peek().kind == tokenOperator && p.peek().text == operator {
+		p.position++
+		return true
+	}
+	return false
+}
+
+// expect consumes the given operator or returns an error.
+func (p *parser) expect(operator string) (err error) {
+	if !p.accept(operator) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+// unexpected returns an error describing the next token.
+func (p *parser) unexpected() error {
+	var next token = p.peek()
+	if next.kind == tokenEnd {
+		return fmt.Errorf("Error: unexpected end of expression at column %v", next.column)
+	}
+	return fmt.Errorf("Error: unexpected %q at column %v", next.text, next.column)
+}
+
+// parseBinary parses operators of the given precedence level and higher ones.
+func (p *parser) parseBinary(level int) (ret node, err error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+
+	ret, err = p.parseBinary(level + 1)
+	if err != nil {
+		return
+	}
+	for {
+		var next token = p.peek()
+		if next.kind != tokenOperator || !slices.Contains(binaryLevels[level], next.text) {
+			return
+		}
+		p.next()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		ret = &binary{operator: next.text, left: ret, right: right}
+	}
+}
+
+// parseUnary parses unary operators and operands.
+func (p *parser) parseUnary() (ret node, err error) {
+	for _, operator := range []string{"-", "+", "!"} {
+		if p.accept(operator) {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &unary{operator: operator, operand: operand}, nil
+		}
+	}
+
+	var next token = p.peek()
+	switch {
+	case next.kind == tokenNumber:
+		p.next()
+		return number(next.value), nil
+
+	case next.kind == tokenName:
+		p.next()
+		return p.parseName(next)
+
+	case p.accept("("):
+		ret, err = p.parseBinary(0)
+		if err != nil {
+			return
+		}
+		return ret, p.expect(")")
+	}
+	return nil, p.unexpected()
+}
+
+// parseName parses names and their brackets.
+func (p *parser) parseName(word token) (ret node, err error) {
+	switch word.text {
+	case "ptr", "hits":
+		return variable(word.text), nil
+
+	case "cell":
+		if !p.accept("[") {
+			return variable(word.text), nil
+		}
+
+	case "mem":
+		if err = p.expect("["); err != nil {
+			return
+		}
+
+	default:
+		return nil, fmt.Errorf("Error: unknown name %q at column %v, expect cell, mem, ptr or hits", word.text, word.column)
+	}
+
+	// Address in brackets
+	address, err := p.parseBinary(0)
+	if err != nil {
+		return
+	}
+	if err = p.expect("]"); err != nil {
+		return
+	}
+	return &index{relative: word.text == "cell", address: address}, nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ir
+
+import "github.com/Anslen/Bfck/codeManager/code"
+
+// Span is a range of source text, both ends included.
+//
+// CAUSION: lines start from 1, columns count runes and start from 0
+type Span struct {
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+// Join returns a span from the begin of span to the end of other.
+func (span Span) Join(other Span) Span {
+	span.EndLine = other.EndLine
+	span.EndColumn = other.EndColumn
+	return span
+}
+
+// Instruction is a single operator inside a basic block.
+type Instruction struct {
+	Operator code.Operator // Any operator except brackets and multiply
+	Value    uint64        // Auxiliary data, see code.Operator constants
+	Offset   int           // Offset from pointer of the cell changed, see code.Code.Offsets
+	Span     Span
+}
+
+// Node is a basic block or a loop.
+type Node interface {
+	Span() Span
+}
+
+// Block is a basic block, a run of instructions without loops.
+type Block struct {
+	Instructions []Instruction
+}
+
+// Loop is a bracket loop with its body.
+type Loop struct {
+	Body       []Node
+	Begin      Span             // Left bracket
+	End        Span             // Right bracket
+	MulTargets []code.MulTarget // If not nil, lowered with a multiply operator before the loop
+}
+
+// Program is the whole code as a tree of blocks and loops.
+//
+// In debug mode no optimisation may cross a line begin, so every line keeps its own begin index.
+type Program struct {
+	Body      []Node
+	LineCount int
+	DebugFlag bool
+}
+
+// Span returns the span from the first to the last instruction of the block.
+func (b *Block) Span() Span {
+	if len(b.Instructions) == 0 {
+		return Span{}
+	}
+	return b.Instructions[0].Span.Join(b.Instructions[len(b.Instructions)-1].Span)
+}
+
+// Span returns the span from the left bracket to the right bracket of the loop.
+func (l *Loop) Span() Span {
+	return l.Begin.Join(l.End)
+}
+
+// AppendNode appends a node to the node list, a block is merged into the last node if it is also a block.
+//
+// The last block of the list may be modified, empty blocks are dropped.
+func AppendNode(nodes []Node, node Node) []Node {
+	if block, isBlock := node.(*Block); isBlock {
+		if len(block.Instructions) == 0 {
+			return nodes
+		}
+		if len(nodes) != 0 {
+			if last, isBlock := nodes[len(nodes)-1].(*Block); isBlock {
+				last.Instructions = append(last.Instructions, block.Instructions...)
+				return nodes
+			}
+		}
+	}
+	return append(nodes, node)
+}
+
+// AppendInstruction appends an instruction to the last block of the node list, adding a block if needed.
+func AppendInstruction(nodes []Node, instruction Instruction) []Node {
+	return AppendNode(nodes, &Block{Instructions: []Instruction{instruction}})
+}
+
+// Rewrite replaces every node list of the program with the result of rewrite, inner loops first.
+func (p *Program) Rewrite(rewrite func(nodes []Node) []Node) {
+	p.Body = rewriteNodes(p.Body, rewrite)
+}
+
+func rewriteNodes(nodes []Node, rewrite func(nodes []Node) []Node) []Node {
+	for _, node := range nodes {
+		if loop, isLoop := node.(*Loop); isLoop {
+			loop.Body = rewriteNodes(loop.Body, rewrite)
+		}
+	}
+	return rewrite(nodes)
+}
+
+// ForEachBlock calls visit for every block of the program in source order.
+func (p *Program) ForEachBlock(visit func(block *Block)) {
+	forEachBlock(p.Body, visit)
+}
+
+func forEachBlock(nodes []Node, visit func(block *Block)) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *Block:
+			visit(node)
+
+		case *Loop:
+			forEachBlock(node.Body, visit)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ir_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	"github.com/Anslen/Bfck/codeManager/ir"
+)
+
+// at returns the span of a single character.
+func at(line, column int) ir.Span {
+	return ir.Span{Line: line, Column: column, EndLine: line, EndColumn: column}
+}
+
+func TestParse(t *testing.T) {
+	program, err := ir.Parse("+a\n[>-]\n.", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expect *ir.Program = &ir.Program{
+		Body: []ir.Node{
+			&ir.Block{Instructions: []ir.Instruction{{Operator: code.OpAdd, Value: 1, Span: at(1, 0)}}},
+			&ir.Loop{
+				Body: []ir.Node{&ir.Block{Instructions: []ir.Instruction{
+					{Operator: code.OpMoveRight, Value: 1, Span: at(2, 1)},
+					{Operator: code.OpSub, Value: 1, Span: at(2, 2)},
+				}}},
+				Begin: at(2, 0),
+				End:   at(2, 3),
+			},
+			&ir.Block{Instructions: []ir.Instruction{{Operator: code.OpOutput, Value: 1, Span: at(3, 0)}}},
+		},
+		LineCount: 3,
+	}
+	if !reflect.DeepEqual(program, expect) {
+		t.Errorf("parsed to %+v, expect %+v", program, expect)
+	}
+	if span := program.Body[1].Span(); span != (ir.Span{Line: 2, Column: 0, EndLine: 2, EndColumn: 3}) {
+		t.Errorf("loop span is %+v", span)
+	}
+}
+
+func TestLower(t *testing.T) {
+	// +[>++<-] with the loop marked as multiply and a set by offset after it
+	var program *ir.Program = &ir.Program{
+		Body: []ir.Node{
+			&ir.Block{Instructions: []ir.Instruction{{Operator: code.OpAdd, Value: 1, Span: at(1, 0)}}},
+			&ir.Loop{
+				Body: []ir.Node{&ir.Block{Instructions: []ir.Instruction{
+					{Operator: code.OpAdd, Value: 2, Offset: 1, Span: ir.Span{Line: 1, Column: 2, EndLine: 1, EndColumn: 4}},
+					{Operator: code.OpSub, Value: 1, Span: at(1, 6)},
+				}}},
+				Begin:      at(1, 1),
+				End:        at(1, 7),
+				MulTargets: []code.MulTarget{{Offset: 1, Factor: 2}},
+			},
+			&ir.Block{Instructions: []ir.Instruction{{Operator: code.OpSet, Value: 7, Offset: -1, Span: at(2, 0)}}},
+		},
+		LineCount: 2,
+	}
+	var c *code.Code = ir.Lower(program)
+
+	var expect *code.Code = &code.Code{
+		Operators:     []code.Operator{code.OpAdd, code.OpMultiply, code.OpLeftBracket, code.OpAdd, code.OpSub, code.OpRightBracket, code.OpSet},
+		Auxiliary:     []uint64{1, 0, 6, 2, 1, 3, 7},
+		SourceLines:   []int{1, 1, 1, 1, 1, 1, 2},
+		SourceColumns: []int{1, 2, 2, 3, 7, 8, 1},
+		Offsets:       []int{0, 0, 0, 1, 0, 0, -1},
+		MulTargets:    [][]code.MulTarget{{{Offset: 1, Factor: 2}}},
+		CodeCount:     7,
+		LineCount:     2,
+	}
+	if !reflect.DeepEqual(c, expect) {
+		t.Errorf("lowered to %+v, expect %+v", c, expect)
+	}
+}
+
+// TestLineBegins checks that an empty line begins at the next operator, and at -1 if there is none.
+func TestLineBegins(t *testing.T) {
+	var cases = []struct {
+		source string
+		expect []int
+	}{
+		{"+\n\n[-\n\n]\n\n", []int{0, 1, 1, 3, 3, -1}},
+		{"\n\n+", []int{0, 0, 0}},
+		{"+ comment\n.", []int{0, 1}},
+	}
+	for _, each := range cases {
+		program, err := ir.Parse(each.source, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ir.Lower(program).LineBegins; !slices.Equal(got, each.expect) {
+			t.Errorf("%q: line begins are %v, expect %v", each.source, got, each.expect)
+		}
+	}
+
+	// Only debug mode keeps line begins
+	program, err := ir.Parse("+\n.", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ir.Lower(program).LineBegins; got != nil {
+		t.Errorf("line begins are %v without debug mode", got)
+	}
+}
+
+// recordPass appends its name to the record when run.
+type recordPass struct {
+	name   string
+	record *[]string
+}
+
+func (p recordPass) Run(program *ir.Program) {
+	*p.record = append(*p.record, p.name)
+}
+
+func TestPassManagerOrder(t *testing.T) {
+	var record []string
+	ir.NewPassManager(recordPass{"first", &record}, recordPass{"second", &record}).Run(&ir.Program{})
+	if !slices.Equal(record, []string{"first", "second"}) {
+		t.Errorf("passes ran in order %v", record)
+	}
+}
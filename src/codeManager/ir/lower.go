@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ir
+
+import "github.com/Anslen/Bfck/codeManager/code"
+
+// Lower converts the program into code, with jump indices and, in debug mode, line begins.
+func Lower(program *Program) (ret *code.Code) {
+	ret = code.New(program.DebugFlag)
+	lowerNodes(ret, program.Body)
+	ret.CodeCount = len(ret.Operators)
+	ret.LineCount = uint64(program.LineCount)
+
+	if program.DebugFlag {
+		setLineBegins(ret, program.LineCount)
+	}
+	return
+}
+
+func lowerNodes(result *code.Code, nodes []Node) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *Block:
+			for _, instruction := range node.Instructions {
+				push(result, instruction.Operator, instruction.Value, instruction.Span, instruction.Offset)
+			}
+
+		case *Loop:
+			// Multiply operator runs the following loop in one step
+			if node.MulTargets != nil {
+				push(result, code.OpMultiply, uint64(len(result.MulTargets)), node.Begin, 0)
+				result.MulTargets = append(result.MulTargets, node.MulTargets)
+			}
+
+			// Set jump indices after body is lowered
+			var leftBracketIndex int = len(result.Operators)
+			push(result, code.OpLeftBracket, 0, node.Begin, 0)
+			lowerNodes(result, node.Body)
+			push(result, code.OpRightBracket, uint64(leftBracketIndex+1), node.End, 0)
+			result.Auxiliary[leftBracketIndex] = uint64(len(result.Operators))
+		}
+	}
+}
+
+// push appends an operator starting at the begin of span.
+func push(result *code.Code, op code.Operator, auxiliary uint64, span Span, offset int) {
+	result.Operators = append(result.Operators, op)
+	result.Auxiliary = append(result.Auxiliary, auxiliary)
+	result.SourceLines = append(result.SourceLines, span.Line)
+	result.SourceColumns = append(result.SourceColumns, span.Column+1)
+	result.Offsets = append(result.Offsets, offset)
+}
+
+// setLineBegins sets the begin index of each line to its first operator, or the next line's if it has none.
+//
+// If no operator follows, begin index is -1.
+func setLineBegins(result *code.Code, lineCount int) {
+	result.LineBegins = make([]int, lineCount)
+	var index int = 0
+	for line := 1; line <= lineCount; line++ {
+		for index < result.CodeCount && result.SourceLines[index] < line {
+			index++
+		}
+		if index == result.CodeCount {
+			result.LineBegins[line-1] = -1
+		} else {
+			result.LineBegins[line-1] = index
+		}
+	}
+}
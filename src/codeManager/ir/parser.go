@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Anslen/Bfck/codeManager/bracketNotCloseError"
+	"github.com/Anslen/Bfck/codeManager/code"
+)
+
+// openLoop is a loop whose right bracket is not found yet.
+type openLoop struct {
+	loop     *Loop
+	outer    []Node // Node list the loop belongs to
+	lineText string // Text line of the left bracket, for error message
+}
+
+// Parse parses the given code text into a program, with one instruction for each operator character.
+func Parse(codeText string, debugFlag bool) (ret *Program, err error) {
+	// Return early if codeText is empty
+	if len(codeText) == 0 {
+		err = errors.New("Error: Code is empty")
+		return
+	}
+
+	var body []Node = make([]Node, 0)
+	var openLoops []openLoop = make([]openLoop, 0)
+	var lineCount int = 0
+
+	// Lookup each character in codeText
+	for line := range strings.Lines(codeText) {
+		lineCount++
+		var columnIndex int = -1
+		for _, char := range line {
+			columnIndex++
+			var span Span = Span{Line: lineCount, Column: columnIndex, EndLine: lineCount, EndColumn: columnIndex}
+
+			switch op := code.ToOperator(char); op {
+			case code.Invalid:
+				// Comment
+
+			case code.OpLeftBracket:
+				// Parse loop body into a new list
+				openLoops = append(openLoops, openLoop{loop: &Loop{Begin: span}, outer: body, lineText: line})
+				body = make([]Node, 0)
+
+			case code.OpRightBracket:
+				if len(openLoops) == 0 {
+					err = bracketNotCloseError.New(lineCount, columnIndex, line)
+					return
+				}
+				var open openLoop = openLoops[len(openLoops)-1]
+				openLoops = openLoops[:len(openLoops)-1]
+
+				// Check empty loop and warn
+				if len(body) == 0 {
+					fmt.Fprintf(os.Stderr, "Warning: Empty loop at line %v\n", lineCount)
+				}
+
+				open.loop.Body = body
+				open.loop.End = span
+				body = AppendNode(open.outer, open.loop)
+
+			default:
+				body = AppendInstruction(body, Instruction{Operator: op, Value: 1, Span: span})
+			}
+		}
+	}
+
+	// Check for unclosed brackets, report the outermost one
+	if len(openLoops) != 0 {
+		var begin Span = openLoops[0].loop.Begin
+		err = bracketNotCloseError.New(begin.Line, begin.Column, openLoops[0].lineText)
+		return
+	}
+
+	ret = &Program{
+		Body:      body,
+		LineCount: lineCount,
+		DebugFlag: debugFlag,
+	}
+	return
+}
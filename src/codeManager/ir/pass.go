@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package ir
+
+// Pass is an optimisation which rewrites a program in place.
+//
+// A pass must keep the program behaviour, and in debug mode it must not move code across a line begin.
+type Pass interface {
+	Run(program *Program)
+}
+
+// PassManager runs passes on a program in the order they are given.
+type PassManager struct {
+	passes []Pass
+}
+
+func NewPassManager(passes ...Pass) (ret *PassManager) {
+	ret = &PassManager{
+		passes: make([]Pass, 0, len(passes)),
+	}
+	ret.passes = append(ret.passes, passes...)
+	return
+}
+
+// Run runs all passes on the program.
+func (pm *PassManager) Run(program *Program) {
+	for _, pass := range pm.passes {
+		pass.Run(program)
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package jit
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+)
+
+// Registers used by machine code:
+//
+//	rdi  address of state
+//	rsi  address of cell 0
+//	r8   memory pointer
+//	r9   loop iterations left before yielding
+//	rax, rcx, rdx  scratch
+//
+// Machine code starts with an entry which loads registers from state and jumps to the resume
+// address, then the common exit which saves them back and returns to Go.
+
+// Offsets of state fields, all fit in a signed byte.
+var (
+	stateTape      = byte(unsafe.Offsetof(state{}.tape))
+	statePointer   = byte(unsafe.Offsetof(state{}.pointer))
+	stateLowGuard  = byte(unsafe.Offsetof(state{}.lowGuard))
+	stateHighGuard = byte(unsafe.Offsetof(state{}.highGuard))
+	stateResume    = byte(unsafe.Offsetof(state{}.resume))
+	stateExit      = byte(unsafe.Offsetof(state{}.exit))
+	stateOperand   = byte(unsafe.Offsetof(state{}.operand))
+	stateOutput    = byte(unsafe.Offsetof(state{}.output))
+	stateOutLen    = byte(unsafe.Offsetof(state{}.outLen))
+	stateOutCap    = byte(unsafe.Offsetof(state{}.outCap))
+	stateBudget    = byte(unsafe.Offsetof(state{}.budget))
+)
+
+// exitSize is the length of the exit sequence.
+const exitSize = 24
+
+// Condition codes of jcc rel32, the second opcode byte.
+const (
+	condEqual    = 0x84
+	condNotEqual = 0x85
+	condLess     = 0x8C
+	condGreater  = 0x8F
+)
+
+// fixup is a rel32 field to be patched once its target is known.
+type fixup struct {
+	position int // Offset of the rel32 field
+	target   int // Operator index, or stub index for stub fixups
+}
+
+// stub exits to Go for the given reason, then continues at resume.
+type stub struct {
+	exit   int
+	resume int
+}
+
+type assembler struct {
+	machine    []byte
+	cellWidth  int   // Bytes of each cell
+	labels     []int // Machine code offset of each operator, and of the end of code
+	exitCommon int
+	fixups     []fixup // Jumps to operators
+	stubs      []stub
+	stubFixups []fixup // Jumps to stubs
+}
+
+// compile translates code to machine code for cells of cellWidth bytes, the body starts at bodyStart.
+func compile(c *code.Code, cellWidth int) (machine []byte, bodyStart int, err error) {
+	var a *assembler = &assembler{
+		machine:   make([]byte, 0, 16*c.CodeCount+64),
+		cellWidth: cellWidth,
+		labels:    make([]int, c.CodeCount+1),
+	}
+	for index := range a.labels {
+		a.labels[index] = -1
+	}
+
+	// Entry, load registers and continue at resume address
+	a.emit(0x48, 0x8B, 0x77, stateTape)    // mov rsi, [rdi+tape]
+	a.emit(0x4C, 0x8B, 0x47, statePointer) // mov r8, [rdi+pointer]
+	a.emit(0x4C, 0x8B, 0x4F, stateBudget)  // mov r9, [rdi+budget]
+	a.emit(0xFF, 0x67, stateResume)        // jmp [rdi+resume]
+
+	// Common exit, save registers and return to Go
+	a.exitCommon = len(a.machine)
+	a.emit(0x4C, 0x89, 0x47, statePointer) // mov [rdi+pointer], r8
+	a.emit(0x4C, 0x89, 0x4F, stateBudget)  // mov [rdi+budget], r9
+	a.emit(0xC3)                           // ret
+
+	bodyStart = len(a.machine)
+	for index := 0; index < c.CodeCount; {
+		a.labels[index] = len(a.machine)
+		if index, err = a.operator(c, index); err != nil {
+			return
+		}
+	}
+	a.labels[c.CodeCount] = len(a.machine)
+	a.exit(exitFinish, len(a.machine))
+
+	// Stubs after the body, keeping hot code together
+	var stubOffsets []int = make([]int, len(a.stubs))
+	for index, s := range a.stubs {
+		stubOffsets[index] = len(a.machine)
+		a.exit(s.exit, s.resume)
+	}
+
+	// Patch jumps
+	for _, f := range a.fixups {
+		if a.labels[f.target] < 0 {
+			panic("JIT: jump to operator not compiled")
+		}
+		a.patch(f.position, a.labels[f.target])
+	}
+	for _, f := range a.stubFixups {
+		a.patch(f.position, stubOffsets[f.target])
+	}
+	return a.machine, bodyStart, nil
+}
+
+// operator compiles the operator at index, and returns the index of the next operator to compile.
+func (a *assembler) operator(c *code.Code, index int) (next int, err error) {
+	var auxiliary uint64 = c.Auxiliary[index]
+	var offset int = c.Offsets[index]
+	next = index + 1
+
+	switch c.Operators[index] {
+	case code.OpAdd:
+		a.cellImmediate([]byte{0x80}, []byte{0x81}, 0, offset, auxiliary)
+
+	case code.OpSub:
+		a.cellImmediate([]byte{0x80}, []byte{0x81}, 5, offset, auxiliary)
+
+	case code.OpClear:
+		a.cellImmediate([]byte{0xC6}, []byte{0xC7}, 0, offset, 0)
+
+	case code.OpSet:
+		a.cellImmediate([]byte{0xC6}, []byte{0xC7}, 0, offset, auxiliary)
+
+	case code.OpMoveLeft:
+		if auxiliary > maxOffset {
+			return 0, fmt.Errorf("%w: move too long", ErrUnsupported)
+		}
+		a.movePointer(-int(auxiliary), -1)
+
+	case code.OpMoveRight:
+		if auxiliary > maxOffset {
+			return 0, fmt.Errorf("%w: move too long", ErrUnsupported)
+		}
+		a.movePointer(int(auxiliary), -1)
+
+	case code.OpLeftBracket:
+		a.compareZero()
+		a.jumpOperator(condEqual, int(auxiliary))
+
+		// Empty loop never ends once entered, warn and spin
+		if c.Operators[index+1] == code.OpRightBracket {
+			a.emit(0x48, 0xC7, 0x47, stateOperand) // mov qword [rdi+operand], imm32
+			a.emit32(uint32(index + 1))
+			var spin int = len(a.machine) + exitSize
+			a.exit(exitInfiniteLoop, spin)
+			a.emit(0x49, 0xFF, 0xC9) // dec r9
+			a.emit(0x0F, condNotEqual)
+			a.emit32(uint32(spin - (len(a.machine) + 4)))
+			a.exit(exitYield, spin)
+			next = int(auxiliary)
+		}
+
+	case code.OpRightBracket:
+		a.emit(0x49, 0xFF, 0xC9) // dec r9
+		a.jumpStub(condEqual, exitYield, len(a.machine)+6)
+		a.compareZero()
+		a.jumpOperator(condNotEqual, int(auxiliary))
+
+	case code.OpInput:
+		a.exit(exitInput, len(a.machine)+exitSize)
+
+	case code.OpOutput:
+		a.loadCell(0, 1)                      // movzx eax, byte cell
+		a.emit(0x48, 0x8B, 0x57, stateOutLen) // mov rdx, [rdi+outLen]
+		a.emit(0x48, 0x8B, 0x4F, stateOutput) // mov rcx, [rdi+output]
+		a.emit(0x88, 0x04, 0x11)              // mov [rcx+rdx], al
+		a.emit(0x48, 0xFF, 0xC2)              // inc rdx
+		a.emit(0x48, 0x89, 0x57, stateOutLen) // mov [rdi+outLen], rdx
+		a.emit(0x48, 0x3B, 0x57, stateOutCap) // cmp rdx, [rdi+outCap]
+		a.jumpStub(condEqual, exitFlush, len(a.machine)+6)
+
+	case code.OpScan:
+		var stride int = int(int64(auxiliary))
+		if stride > maxOffset || stride < -maxOffset {
+			return 0, fmt.Errorf("%w: scan stride too long", ErrUnsupported)
+		}
+		var scan int = len(a.machine)
+		a.compareZero()
+		a.emit(0x0F, condEqual)
+		var done int = len(a.machine)
+		a.emit32(0)
+		a.movePointer(stride, scan)
+		a.emit(0xE9) // jmp scan
+		a.emit32(uint32(scan - (len(a.machine) + 4)))
+		a.patch(done, len(a.machine))
+
+	case code.OpMultiply:
+		a.multiply(c.MulTargets[auxiliary])
+		// Skip the loop, which follows the operator
+		next = int(c.Auxiliary[index+1])
+
+	default:
+		panic("JIT: unknown operator")
+	}
+	return
+}
+
+// multiply adds loop cell times factor to each target, then clears loop cell.
+//
+// Factors are truncated to 32 bits, which keeps results modulo cell width.
+func (a *assembler) multiply(targets []code.MulTarget) {
+	a.loadCell(0, a.cellWidth)
+	for _, target := range targets {
+		a.emit(0x69, 0xC8) // imul ecx, eax, imm32
+		a.emit32(uint32(target.Factor))
+		a.cellOperation([]byte{0x00}, []byte{0x01}, 1, target.Offset) // add cell, ecx
+	}
+	a.cellImmediate([]byte{0xC6}, []byte{0xC7}, 0, 0, 0)
+}
+
+// movePointer moves pointer by steps and checks guards, growing tape continues at resume,
+// or right after the checks if resume is -1. The pointer is already moved when resuming.
+func (a *assembler) movePointer(steps int, resume int) {
+	var firstStub int = len(a.stubs)
+	if steps >= 0 {
+		a.emit(0x49, 0x81, 0xC0) // add r8, imm32
+		a.emit32(uint32(steps))
+	} else {
+		a.emit(0x49, 0x81, 0xE8) // sub r8, imm32
+		a.emit32(uint32(-steps))
+	}
+	a.emit(0x4C, 0x3B, 0x47, stateLowGuard) // cmp r8, [rdi+lowGuard]
+	a.jumpStub(condLess, exitGrow, resume)
+	a.emit(0x4C, 0x3B, 0x47, stateHighGuard) // cmp r8, [rdi+highGuard]
+	a.jumpStub(condGreater, exitGrow, resume)
+
+	// Resume after the move instead of moving again
+	if resume == -1 {
+		for index := firstStub; index < len(a.stubs); index++ {
+			a.stubs[index].resume = len(a.machine)
+		}
+	}
+}
+
+// compareZero compares the current cell with zero.
+func (a *assembler) compareZero() {
+	// cmp cell, imm8 sign extended for wide cells
+	if a.cellWidth == 1 {
+		a.cellOperation([]byte{0x80}, nil, 7, 0)
+	} else {
+		a.cellOperation(nil, []byte{0x83}, 7, 0)
+	}
+	a.emit(0)
+}
+
+// loadCell zero extends the lowest bytes of the current cell into eax.
+func (a *assembler) loadCell(offset int, bytes int) {
+	switch bytes {
+	case 1:
+		a.cellOperationWidth(1, []byte{0x0F, 0xB6}, 0, offset)
+	case 2:
+		a.cellOperationWidth(2, []byte{0x0F, 0xB7}, 0, offset)
+	case 4:
+		a.cellOperationWidth(4, []byte{0x8B}, 0, offset)
+	}
+}
+
+// cellImmediate emits an operation on the cell at offset with an immediate truncated to cell width,
+// byteOpcode is used for 8-bit cells and wideOpcode for others.
+func (a *assembler) cellImmediate(byteOpcode, wideOpcode []byte, reg byte, offset int, value uint64) {
+	a.cellOperation(byteOpcode, wideOpcode, reg, offset)
+	switch a.cellWidth {
+	case 1:
+		a.emit(byte(value))
+	case 2:
+		a.emit(byte(value), byte(value>>8))
+	case 4:
+		a.emit32(uint32(value))
+	}
+}
+
+// cellOperation emits opcode with the cell at offset as memory operand and reg in ModRM reg field.
+func (a *assembler) cellOperation(byteOpcode, wideOpcode []byte, reg byte, offset int) {
+	if a.cellWidth == 1 {
+		a.cellOperationWidth(1, byteOpcode, reg, offset)
+	} else {
+		a.cellOperationWidth(a.cellWidth, wideOpcode, reg, offset)
+	}
+}
+
+// cellOperationWidth emits opcode with operand size of width bytes, addressing [rsi + r8*scale + disp32].
+func (a *assembler) cellOperationWidth(width int, opcode []byte, reg byte, offset int) {
+	if width == 2 {
+		a.emit(0x66) // Operand size prefix
+	}
+	a.emit(0x42) // REX.X for r8 as index
+	a.emit(opcode...)
+
+	// Index scaled by cell width, log2 of 1, 2 or 4
+	var scale byte = byte(a.cellWidth >> 1)
+	a.emit(0x80|reg<<3|0x04, scale<<6|0x06) // ModRM disp32 with SIB, SIB rsi base
+	a.emit32(uint32(int32(offset * a.cellWidth)))
+}
+
+// jumpOperator emits a conditional jump to the operator at target.
+func (a *assembler) jumpOperator(condition byte, target int) {
+	a.emit(0x0F, condition)
+	a.fixups = append(a.fixups, fixup{position: len(a.machine), target: target})
+	a.emit32(0)
+}
+
+// jumpStub emits a conditional jump to a new stub exiting for reason exit.
+func (a *assembler) jumpStub(condition byte, exit int, resume int) {
+	a.emit(0x0F, condition)
+	a.stubFixups = append(a.stubFixups, fixup{position: len(a.machine), target: len(a.stubs)})
+	a.stubs = append(a.stubs, stub{exit: exit, resume: resume})
+	a.emit32(0)
+}
+
+// exit emits exitSize bytes which record resume address and reason, then jump to common exit.
+func (a *assembler) exit(reason int, resume int) {
+	a.emit(0x48, 0x8D, 0x05) // lea rax, [rip+rel32]
+	a.emit32(uint32(resume - (len(a.machine) + 4)))
+	a.emit(0x48, 0x89, 0x47, stateResume) // mov [rdi+resume], rax
+	a.emit(0x48, 0xC7, 0x47, stateExit)   // mov qword [rdi+exit], imm32
+	a.emit32(uint32(reason))
+	a.emit(0xE9) // jmp exitCommon
+	a.emit32(uint32(a.exitCommon - (len(a.machine) + 4)))
+}
+
+// patch sets the rel32 field at position to jump to target.
+func (a *assembler) patch(position int, target int) {
+	binary.LittleEndian.PutUint32(a.machine[position:], uint32(target-(position+4)))
+}
+
+func (a *assembler) emit(bytes ...byte) {
+	a.machine = append(a.machine, bytes...)
+}
+
+func (a *assembler) emit32(value uint32) {
+	a.machine = binary.LittleEndian.AppendUint32(a.machine, value)
+}
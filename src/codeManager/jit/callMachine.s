@@ -0,0 +1,27 @@
+//go:build linux && amd64
+
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+#include "textflag.h"
+
+// func callMachine(entry uintptr, s *state)
+TEXT ·callMachine(SB), NOSPLIT, $8-16
+	MOVQ entry+0(FP), AX
+	MOVQ s+8(FP), DI
+	CALL AX
+	RET
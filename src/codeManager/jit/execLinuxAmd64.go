@@ -0,0 +1,48 @@
+//go:build linux && amd64
+
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package jit
+
+import "syscall"
+
+// mapExecutable copies machine code into new executable memory.
+func mapExecutable(machine []byte) (ret []byte, err error) {
+	ret, err = syscall.Mmap(-1, 0, len(machine), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, err
+	}
+	copy(ret, machine)
+
+	// Never writable and executable at the same time
+	if err = syscall.Mprotect(ret, syscall.PROT_READ|syscall.PROT_EXEC); err != nil {
+		syscall.Munmap(ret)
+		return nil, err
+	}
+	return
+}
+
+// unmapExecutable releases memory returned by mapExecutable.
+func unmapExecutable(machine []byte) error {
+	return syscall.Munmap(machine)
+}
+
+// callMachine calls machine code at entry with the address of s in rdi, implemented in callMachine.s.
+//
+//go:noescape
+func callMachine(entry uintptr, s *state)
@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package jit runs code as x86-64 machine code on linux/amd64.
+//
+// Machine code keeps the tape pointer in a register and exits to Go for input, full output buffer,
+// growing the tape, and yielding to the Go scheduler every so many loop iterations. Go then continues
+// it at the recorded resume address.
+package jit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"unsafe"
+
+	"github.com/Anslen/Bfck/codeManager/code"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// ErrUnsupported is returned by New when the platform or options can't be run by JIT.
+var ErrUnsupported = errors.New("JIT is not supported")
+
+// Reasons of exits from machine code.
+const (
+	exitFinish       = iota
+	exitInput        // ',' needs a byte
+	exitFlush        // Output buffer is full
+	exitGrow         // Pointer is beyond guards
+	exitYield        // Loop budget used up
+	exitInfiniteLoop // Entering an empty loop, operand is its right bracket
+)
+
+const (
+	initialCells = 1 << 16
+	outputSize   = 1 << 12
+	loopBudget   = 1 << 22 // Loop iterations between yields
+	maxOffset    = 1 << 20 // Larger offsets or moves are left to the interpreter
+)
+
+// state is shared with machine code, which addresses fields by their offsets.
+type state struct {
+	tape      uintptr // Address of cell 0
+	pointer   int64   // Memory pointer, saved on exit
+	lowGuard  int64   // Pointer below it needs growing the tape
+	highGuard int64   // Pointer above it needs growing the tape
+	resume    uintptr // Machine code address to continue at
+	exit      uint64  // Reason of the last exit
+	operand   int64   // Extra data of the exit
+	output    uintptr // Address of output buffer
+	outLen    int64   // Bytes in output buffer
+	outCap    int64   // Size of output buffer
+	budget    int64   // Loop iterations left before yielding, saved on exit
+}
+
+// Runner runs code compiled to machine code, it implements coderunner.Engine.
+type Runner struct {
+	machine   []byte // Executable memory
+	bodyStart int    // Offset of the first operator in machine
+	state     *state
+	tape      []byte
+	origin    int // Cell index of address 0 in tape
+	cellWidth int // Bytes of each cell
+	margin    int // Cells kept around pointer, covering every offset in code
+	outBuffer []byte
+	input     *bufio.Reader
+	output    io.Writer
+	eofPolicy coderunner.EOFPolicy
+	warned    bool // Infinite loop warning printed
+}
+
+// New compiles code to machine code, it returns an error wrapping ErrUnsupported if the platform
+// or options can't be run, then caller should use the interpreter instead.
+//
+// Only wrapping cells on an unbounded tape are supported.
+func New(c *code.Code, options coderunner.Options) (ret *Runner, err error) {
+	if c == nil {
+		panic("JIT: code is nil")
+	}
+	var config memory.Config = options.Memory
+	if config.Overflow != memory.OverflowWrap || config.TapeSize != 0 || config.NoNegative || config.MaxCells != 0 {
+		return nil, fmt.Errorf("%w: only wrapping cells on an unbounded tape", ErrUnsupported)
+	}
+	if !memory.IsValidCellWidth(config.CellWidth) {
+		panic("JIT: unsupported cell width")
+	}
+	if config.CellWidth == 0 {
+		config.CellWidth = memory.CellWidth8
+	}
+
+	// Use standard streams by default
+	if options.Input == nil {
+		options.Input = os.Stdin
+	}
+	if options.Output == nil {
+		options.Output = os.Stdout
+	}
+
+	// Compile and map machine code
+	margin, err := codeMargin(c)
+	if err != nil {
+		return
+	}
+	machine, bodyStart, err := compile(c, config.CellWidth/8)
+	if err != nil {
+		return
+	}
+	machine, err = mapExecutable(machine)
+	if err != nil {
+		return
+	}
+
+	ret = &Runner{
+		machine:   machine,
+		bodyStart: bodyStart,
+		state:     new(state),
+		cellWidth: config.CellWidth / 8,
+		margin:    margin,
+		outBuffer: make([]byte, outputSize),
+		input:     bufio.NewReader(options.Input),
+		output:    options.Output,
+		eofPolicy: options.EOF,
+	}
+	runtime.SetFinalizer(ret, (*Runner).Close)
+	return
+}
+
+// Close releases the machine code, Run can't be called after it.
+func (r *Runner) Close() (err error) {
+	if r.machine != nil {
+		err = unmapExecutable(r.machine)
+		r.machine = nil
+	}
+	return
+}
+
+// RuntimeError always returns nil, since supported options never raise runtime errors.
+func (r *Runner) RuntimeError() error {
+	return nil
+}
+
+// Run runs the code from the beginning on a new tape.
+func (r *Runner) Run() (ret coderunner.ReturnCode) {
+	if r.machine == nil {
+		panic("JIT: runner is closed")
+	}
+
+	// New tape with address 0 in the middle
+	var cells int = max(initialCells, 4*r.margin)
+	r.tape = make([]byte, cells*r.cellWidth)
+	r.origin = cells / 2
+	r.updateTape()
+
+	var entry uintptr = uintptr(unsafe.Pointer(&r.machine[0]))
+	r.state.pointer = 0
+	r.state.resume = entry + uintptr(r.bodyStart)
+	r.state.exit = exitFinish
+	r.state.output = uintptr(unsafe.Pointer(&r.outBuffer[0]))
+	r.state.outLen = 0
+	r.state.outCap = int64(len(r.outBuffer))
+	r.state.budget = loopBudget
+	r.warned = false
+
+	for {
+		callMachine(entry, r.state)
+
+		switch r.state.exit {
+		case exitFinish:
+			r.flushOutput()
+			return coderunner.ReturnAfterFinish
+
+		case exitInput:
+			r.flushOutput()
+			r.readInput()
+
+		case exitFlush:
+			r.flushOutput()
+
+		case exitGrow:
+			r.grow()
+
+		case exitYield:
+			r.state.budget = loopBudget
+			runtime.Gosched()
+
+		case exitInfiniteLoop:
+			// Same warning as the interpreter, only once
+			if !r.warned {
+				r.flushOutput()
+				fmt.Fprintf(os.Stderr, "\nWarning: Infinite loop at operator %v\n", r.state.operand)
+				r.warned = true
+			}
+		}
+	}
+}
+
+// flushOutput writes bytes in output buffer, write errors are ignored like the interpreter does.
+func (r *Runner) flushOutput() {
+	if r.state.outLen != 0 {
+		r.output.Write(r.outBuffer[:r.state.outLen])
+		r.state.outLen = 0
+	}
+}
+
+// readInput reads one byte into the current cell, applying EOF policy when input is exhausted.
+func (r *Runner) readInput() {
+	input, err := r.input.ReadByte()
+	var value uint32 = uint32(input)
+	if err != nil {
+		switch r.eofPolicy {
+		case coderunner.EOFZero:
+			value = 0
+
+		case coderunner.EOFMinusOne:
+			value = 0xFFFFFFFF
+
+		case coderunner.EOFUnchanged:
+			return
+		}
+	}
+
+	// Little endian, truncated to cell width
+	var index int = (r.origin + int(r.state.pointer)) * r.cellWidth
+	for i := range r.cellWidth {
+		r.tape[index+i] = byte(value >> (8 * i))
+	}
+}
+
+// grow enlarges the tape so that pointer is inside guards again, keeping cell addresses.
+func (r *Runner) grow() {
+	var cells int = len(r.tape) / r.cellWidth
+	var low int = r.origin + int(r.state.pointer) - r.margin  // Lowest cell index needed
+	var high int = r.origin + int(r.state.pointer) + r.margin // Highest cell index needed
+
+	// At least double the tape towards the side pointer left
+	var newCells int = cells * 2
+	for newCells < high+1 || newCells-cells+low < 0 {
+		newCells *= 2
+	}
+	var newTape []byte = make([]byte, newCells*r.cellWidth)
+	if low < 0 {
+		copy(newTape[(newCells-cells)*r.cellWidth:], r.tape)
+		r.origin += newCells - cells
+	} else {
+		copy(newTape, r.tape)
+	}
+	r.tape = newTape
+	r.updateTape()
+}
+
+// updateTape sets tape address and guards in state after the tape is replaced.
+func (r *Runner) updateTape() {
+	var cells int = len(r.tape) / r.cellWidth
+	r.state.tape = uintptr(unsafe.Pointer(&r.tape[0])) + uintptr(r.origin*r.cellWidth)
+	r.state.lowGuard = int64(-r.origin + r.margin)
+	r.state.highGuard = int64(cells - r.origin - 1 - r.margin)
+}
+
+// codeMargin returns cells to keep on both sides of pointer, so that any offset in code stays inside tape.
+func codeMargin(c *code.Code) (ret int, err error) {
+	ret = 16
+	for index, operator := range c.Operators {
+		var offset int = c.Offsets[index]
+		if operator == code.OpMultiply {
+			for _, target := range c.MulTargets[c.Auxiliary[index]] {
+				offset = max(offset, target.Offset, -target.Offset)
+			}
+		}
+		ret = max(ret, offset+16, -offset+16)
+	}
+	if ret > maxOffset {
+		return 0, fmt.Errorf("%w: offset too large", ErrUnsupported)
+	}
+	return
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package jit_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/codeManager/jit"
+	"github.com/Anslen/Bfck/internal/testprograms"
+	"github.com/Anslen/Bfck/memory"
+)
+
+// TestSameAsInterpreter runs every program on JIT and on the interpreter with the same options.
+func TestSameAsInterpreter(t *testing.T) {
+	// Wide cells only with -O2, which clears them without counting to the maximum
+	var configs = []struct {
+		level     codeanalyser.OptLevel
+		cellWidth int
+	}{
+		{codeanalyser.O0, memory.CellWidth8},
+		{codeanalyser.O2, memory.CellWidth8},
+		{codeanalyser.O2, memory.CellWidth16},
+		{codeanalyser.O2, memory.CellWidth32},
+	}
+	for _, each := range testprograms.Load(t) {
+		for _, config := range configs {
+			var options coderunner.Options = coderunner.Options{Memory: memory.Config{CellWidth: config.cellWidth}}
+			var got string = compareWithInterpreter(t, each, config.level, options)
+			if config.cellWidth == memory.CellWidth8 && got != each.Output {
+				t.Errorf("%v -O%v: JIT wrote %q, expect %q", each.Name, config.level, got, each.Output)
+			}
+		}
+	}
+}
+
+// TestEOFPolicies reads beyond the end of input with every EOF policy.
+func TestEOFPolicies(t *testing.T) {
+	var eof testprograms.Program = testprograms.Program{Name: "eof", Source: ",.,.,.+.", Input: "A"}
+	for _, policy := range []coderunner.EOFPolicy{coderunner.EOFZero, coderunner.EOFUnchanged, coderunner.EOFMinusOne} {
+		compareWithInterpreter(t, eof, codeanalyser.O2, coderunner.Options{EOF: policy})
+	}
+}
+
+// compareWithInterpreter runs the program on JIT and on the interpreter and compares their output,
+// skipping the test if JIT is not supported.
+func compareWithInterpreter(t *testing.T, each testprograms.Program, level codeanalyser.OptLevel, options coderunner.Options) (got string) {
+	t.Helper()
+	c, err := codeanalyser.Analyse(each.Source, false, level)
+	if err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+
+	var want bytes.Buffer
+	options.Input, options.Output = strings.NewReader(each.Input), &want
+	if ret := coderunner.New(c, false, options).Run(); ret != coderunner.ReturnAfterFinish {
+		t.Fatalf("%v: interpreter returned %v", each.Name, ret)
+	}
+
+	var output bytes.Buffer
+	options.Input, options.Output = strings.NewReader(each.Input), &output
+	runner, err := jit.New(c, options)
+	if errors.Is(err, jit.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("%v: %v", each.Name, err)
+	}
+	defer runner.Close()
+	if ret := runner.Run(); ret != coderunner.ReturnAfterFinish {
+		t.Errorf("%v: JIT returned %v", each.Name, ret)
+	}
+
+	got = output.String()
+	if got != want.String() {
+		t.Errorf("%v -O%v %+v: JIT wrote %q, interpreter wrote %q", each.Name, level, options.Memory, got, want.String())
+	}
+	return
+}
+
+// TestGrowTape moves far enough to grow the tape several times in both directions, every cell
+// written on the way must keep its value after growing.
+func TestGrowTape(t *testing.T) {
+	for _, move := range []string{">", "<"} {
+		var back string = map[string]string{">": "<", "<": ">"}[move]
+		var source string = "+" + strings.Repeat(move+"+", 100000) + strings.Repeat("."+back, 100000) + "."
+
+		c, err := codeanalyser.Analyse(source, false, codeanalyser.O2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var output bytes.Buffer
+		runner, err := jit.New(c, coderunner.Options{Output: &output})
+		if errors.Is(err, jit.ErrUnsupported) {
+			t.Skip(err)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		runner.Run()
+		runner.Close()
+
+		if output.String() != strings.Repeat("\x01", 100001) {
+			t.Errorf("moving %q: cells changed after growing tape", move)
+		}
+	}
+}
+
+// TestScanGrowTape scans over cells up to the edge of a new tape, which has cells from address -512
+// to 511, so the scan itself grows the tape. The cell before the stop must still be 1.
+func TestScanGrowTape(t *testing.T) {
+	var cases = []struct {
+		count  int
+		stride int
+	}{
+		{512, 1}, {513, -1}, {171, 3}, {257, -2}, {5000, 1}, {5000, -1},
+	}
+	for _, each := range cases {
+		var forward, back string = ">", "<"
+		if each.stride < 0 {
+			forward, back = back, forward
+		}
+		var step string = strings.Repeat(forward, max(each.stride, -each.stride))
+		var source string = strings.Repeat("+"+step, each.count-1) + "+" + strings.Repeat(back, (each.count-1)*len(step)) +
+			"[" + step + "]" + strings.Repeat("+", 65) + "." + strings.Repeat(back, len(step)) + "."
+		var scan testprograms.Program = testprograms.Program{Name: fmt.Sprintf("scan %v by %v", each.count, each.stride), Source: source}
+		if got := compareWithInterpreter(t, scan, codeanalyser.O2, coderunner.Options{}); got != "A\x01" {
+			t.Errorf("%v: wrote %q, expect %q", scan.Name, got, "A\x01")
+		}
+	}
+}
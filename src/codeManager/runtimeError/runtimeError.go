@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package runtimeError
+
+import "fmt"
+
+type RuntimeError struct {
+	index int
+	line  int
+	cause error
+}
+
+// Error implements the error interface for RuntimeError.
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("Error: %v at operator %v, line %v", e.cause.Error(), e.index, e.line)
+}
+
+// Unwrap returns the underlying error, so errors.Is works with memory errors.
+func (e *RuntimeError) Unwrap() error {
+	return e.cause
+}
+
+// Index returns the index of the operator causing the error.
+func (e *RuntimeError) Index() int {
+	return e.index
+}
+
+// Line returns the source line of the operator causing the error.
+func (e *RuntimeError) Line() int {
+	return e.line
+}
+
+// New creates a new RuntimeError raised by the operator at the given index and source line.
+//
+// CAUSION: index start from 0, line start from 1
+func New(index int, line int, cause error) (ret *RuntimeError) {
+	ret = &RuntimeError{
+		index: index,
+		line:  line,
+		cause: cause,
+	}
+	return
+}
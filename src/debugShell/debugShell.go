@@ -20,11 +20,12 @@ package debugshell
 import (
 	"bufio"
 	"fmt"
-	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/codeManager/expression"
 )
 
 const HELP_STRING string = "Execute commands:\n" +
@@ -33,17 +34,27 @@ const HELP_STRING string = "Execute commands:\n" +
 	"s[tep] [times]           : Step by times, default 1\n" +
 	"d[etailed] [times]       : Detailed step for specified times, default run until finish\n" +
 	"u[ntil]                  : Run until loop([]) finish\n" +
+	"rs|reverse-step [times]  : Step back by times, default 1\n" +
+	"rc|reverse-continue      : Run backwards to the last breakpoint or watch hit\n" +
+	"ru|reverse-until         : Run backwards to the beginning of current loop\n" +
 	"\nDebug commands:\n" +
 	"stop <index>             : Stop execution at specified operator index\n" +
-	"w[atch] <address>        : Watch memory at address\n" +
-	"b[reak] <line>           : Set breakpoint at specified line\n" +
-	"w[atch] <address>        : Watch memory at address\n" +
+	"w[atch] <address> [cond] : Stop after memory at address is written, cond can be to <value>,\n" +
+	"                           from <value> or cross <value> to stop only when it changes to,\n" +
+	"                           from or across value\n" +
+	"rw|rwatch <address>      : Stop after memory at address is read by loop test or output\n" +
+	"aw|awatch <address>      : Stop after memory at address is read or written\n" +
+	"b[reak] <where> [if expr]: Set breakpoint at <line>, <line>:<column> or *<operator index>,\n" +
+	"                           only stop when expr is true, which uses cell, cell[offset],\n" +
+	"                           mem[address], ptr, hits and C operators\n" +
+	"ignore <num> <count>     : Pass breakpoint <num> the next count times it would stop\n" +
+	"enable|disable <num>     : Enable or disable breakpoint <num> without deleting it\n" +
 	"del[ete] s|b|w <num>     : Delete breakpoint or watchpoint at specified number\n" +
 	"i[nfo] [s|b|w]           : Information of stop point, breakpoints or watching, default all\n" +
 	"clear [s|b|w]            : Clear all breakpoints or watchpoints, default all\n" +
 	"\nMemory commands:\n" +
 	"ptr                      : Show current memory pointer\n" +
-	"p[eek] [offset [length]] : Peek memory bytes at current pointer with optional offset and length\n" +
+	"p[eek] [offset [length]] : Peek memory cells at current pointer with optional offset and length\n" +
 	"t[ape]                   : Show tape around, equal to peek -10 20\n" +
 	"reset                    : Reset memory tape immediately\n" +
 	"\nOther commands:\n" +
@@ -54,10 +65,13 @@ const HELP_STRING string = "Execute commands:\n" +
 	"\n"
 
 var REG_STEP *regexp.Regexp = regexp.MustCompile(`^s(tep)?( (\d+))?$`)
+var REG_REVERSE_STEP *regexp.Regexp = regexp.MustCompile(`^(rs|reverse-step)( (\d+))?$`)
 var REG_DETAILED *regexp.Regexp = regexp.MustCompile(`^d(etailed)?( (\d+))?$`)
 var REG_STOP *regexp.Regexp = regexp.MustCompile(`^stop (\d+)$`)
-var REG_WATCH *regexp.Regexp = regexp.MustCompile(`^w(atch)? (-?\d+)$`)
-var REG_BREAK *regexp.Regexp = regexp.MustCompile(`^b(reak)? (\d+)$`)
+var REG_WATCH *regexp.Regexp = regexp.MustCompile(`^(w|watch|rw|rwatch|aw|awatch) (-?\d+)( (to|from|cross) (\d+))?$`)
+var REG_BREAK *regexp.Regexp = regexp.MustCompile(`^b(reak)? ((\d+)(:(\d+))?|\*(\d+))( if (.+))?$`)
+var REG_IGNORE *regexp.Regexp = regexp.MustCompile(`^ignore (\d+) (\d+)$`)
+var REG_ENABLE *regexp.Regexp = regexp.MustCompile(`^(enable|disable) (\d+)$`)
 var REG_DELETE *regexp.Regexp = regexp.MustCompile(`^del(ete)? (s|b|w) (\d+)$`)
 var REG_INFO *regexp.Regexp = regexp.MustCompile(`^i(nfo)?( (s|b|w))?$`)
 var REG_CLEAR *regexp.Regexp = regexp.MustCompile(`^clear( (s|b|w))?$`)
@@ -67,6 +81,8 @@ var DEBUG_REG_FUNCTIONS = []func(string, *coderunner.CodeRunner) bool{
 	regMatchStop,
 	regMatchBreak,
 	regMatchWatch,
+	regMatchIgnore,
+	regMatchEnable,
 	regMatchDelete,
 	regMatchInfo,
 	regMatchClear,
@@ -74,17 +90,20 @@ var DEBUG_REG_FUNCTIONS = []func(string, *coderunner.CodeRunner) bool{
 }
 
 // Start starts the debug shell for the given code runner.
-func Start(codeRunner *coderunner.CodeRunner) {
+//
+// Commands are read from input, which should be the same reader given to the code runner
+// so that commands and program input do not steal buffered data from each other.
+func Start(codeRunner *coderunner.CodeRunner, input *bufio.Reader) {
 	var CodeRunning bool = false
-	scanner := bufio.NewScanner(os.Stdin)
 	for {
 		fmt.Print("(Bfck) ")
 
 		// Read command
-		if !scanner.Scan() {
+		line, err := input.ReadString('\n')
+		if err != nil && line == "" {
 			break
 		}
-		command := strings.TrimSpace(scanner.Text())
+		command := strings.TrimSpace(line)
 		if command == "" {
 			continue
 		}
@@ -114,7 +133,7 @@ func matchSimpleCommands(command string, codeRunner *coderunner.CodeRunner, code
 	switch command {
 	case "r", "run":
 		// Run code from beginning and get return code
-		printDebugMessage(codeRunner.Run(), codeRunning)
+		printDebugMessage(codeRunner.Run(), codeRunner, codeRunning)
 		return true
 
 	case "c", "continue":
@@ -125,7 +144,7 @@ func matchSimpleCommands(command string, codeRunner *coderunner.CodeRunner, code
 		}
 
 		// Continue running code
-		printDebugMessage(codeRunner.Continue(), codeRunning)
+		printDebugMessage(codeRunner.Continue(), codeRunner, codeRunning)
 		return true
 
 	case "u", "until":
@@ -137,6 +156,14 @@ func matchSimpleCommands(command string, codeRunner *coderunner.CodeRunner, code
 		}
 		return true
 
+	case "rc", "reverse-continue":
+		printReverseMessage(codeRunner.ReverseContinue(), codeRunner, codeRunning)
+		return true
+
+	case "ru", "reverse-until":
+		printReverseMessage(codeRunner.ReverseUntil(), codeRunner, codeRunning)
+		return true
+
 	case "ptr":
 		var ptr int = codeRunner.GetMemoryPointer()
 		fmt.Printf("Current memory pointer: %d\n\n", ptr)
@@ -181,6 +208,9 @@ func matchRegexCommands(command string, codeRunner *coderunner.CodeRunner, codeR
 	if regMatchDetailed(command, codeRunner, codeRunning) {
 		return true
 	}
+	if regMatchReverseStep(command, codeRunner, codeRunning) {
+		return true
+	}
 
 	for _, function := range DEBUG_REG_FUNCTIONS {
 		if function(command, codeRunner) {
@@ -214,11 +244,35 @@ func regMatchStep(command string, codeRunner *coderunner.CodeRunner, codeRunning
 			fmt.Print("\n\nRunning finished\n\n")
 			break
 		}
+		if ret == coderunner.ReturnRuntimeError || ret == coderunner.ReturnReachWatch {
+			break
+		}
 	}
 	fmt.Print("\n")
 	return true
 }
 
+// regMatchReverseStep regex matching and executing reverse step command.
+func regMatchReverseStep(command string, codeRunner *coderunner.CodeRunner, codeRunning *bool) bool {
+	// Match regex
+	var matches []string = REG_REVERSE_STEP.FindStringSubmatch(command)
+	if matches == nil {
+		return false
+	}
+
+	// Read arguments
+	var times int
+	if matches[3] == "" {
+		times = 1
+	} else {
+		fmt.Sscanf(matches[3], "%d", &times)
+	}
+
+	// Execute reverse step
+	printReverseMessage(codeRunner.ReverseStep(times), codeRunner, codeRunning)
+	return true
+}
+
 func regMatchStop(command string, codeRunner *coderunner.CodeRunner) bool {
 	// Match regex
 	var matches []string = REG_STOP.FindStringSubmatch(command)
@@ -256,8 +310,8 @@ func regMatchDetailed(command string, codeRunner *coderunner.CodeRunner, codeRun
 	var i uint64
 	for i = 0; i < times; i++ {
 		var ret coderunner.ReturnCode = detailedStep(codeRunner, codeRunning)
-		// Break when finished
-		if ret == coderunner.ReturnAfterFinish {
+		// Break when finished, failed or watch hit
+		if ret == coderunner.ReturnAfterFinish || ret == coderunner.ReturnRuntimeError || ret == coderunner.ReturnReachWatch {
 			break
 		}
 	}
@@ -272,12 +326,37 @@ func regMatchBreak(command string, codeRunner *coderunner.CodeRunner) bool {
 		return false
 	}
 
-	// Read arguments
-	var line uint64
-	fmt.Sscanf(matches[2], "%d", &line)
+	// Read condition
+	var condition *expression.Expr
+	if matches[8] != "" {
+		var err error
+		condition, err = expression.Parse(matches[8])
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			return true
+		}
+	}
 
-	// Execute break
-	var message string = codeRunner.AddBreakPoint(line)
+	// Execute break at operator index, line and column, or line
+	var message string
+	switch {
+	case matches[6] != "":
+		var index int
+		fmt.Sscanf(matches[6], "%d", &index)
+		message = codeRunner.AddOperatorBreakPoint(index, condition)
+
+	case matches[5] != "":
+		var line uint64
+		var column int
+		fmt.Sscanf(matches[3], "%d", &line)
+		fmt.Sscanf(matches[5], "%d", &column)
+		message = codeRunner.AddColumnBreakPoint(line, column, condition)
+
+	default:
+		var line uint64
+		fmt.Sscanf(matches[3], "%d", &line)
+		message = codeRunner.AddBreakPoint(line, condition)
+	}
 	fmt.Print(message)
 	return true
 }
@@ -293,13 +372,88 @@ func regMatchWatch(command string, codeRunner *coderunner.CodeRunner) bool {
 	// Read arguments
 	var address int
 	fmt.Sscanf(matches[2], "%d", &address)
+	var kind coderunner.WatchKind
+	switch matches[1] {
+	case "rw", "rwatch":
+		kind = coderunner.WatchRead
+
+	case "aw", "awatch":
+		kind = coderunner.WatchAccess
+
+	default:
+		kind = coderunner.WatchWrite
+	}
+
+	// Read value condition, only for write watchpoints
+	var value int64
+	if matches[3] != "" {
+		if kind != coderunner.WatchWrite {
+			fmt.Print("Error: Only watch command takes to, from or cross\n\n")
+			return true
+		}
+		switch matches[4] {
+		case "to":
+			kind = coderunner.WatchTo
+
+		case "from":
+			kind = coderunner.WatchFrom
+
+		case "cross":
+			kind = coderunner.WatchCross
+		}
+
+		var err error
+		if value, err = strconv.ParseInt(matches[5], 10, 64); err != nil {
+			fmt.Printf("Error: Watch value %v is too large\n\n", matches[5])
+			return true
+		}
+	}
 
 	// Execute watch
-	var message string = codeRunner.AddWatch(address)
+	var message string = codeRunner.AddWatch(address, kind, value)
 	fmt.Print(message)
 	return true
 }
 
+// regMatchIgnore regex matching and executing ignore command.
+func regMatchIgnore(command string, codeRunner *coderunner.CodeRunner) bool {
+	// Match regex
+	var matches []string = REG_IGNORE.FindStringSubmatch(command)
+	if matches == nil {
+		return false
+	}
+
+	// Read arguments
+	var id int
+	fmt.Sscanf(matches[1], "%d", &id)
+	count, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		fmt.Printf("Error: Ignore count %v is too large\n\n", matches[2])
+		return true
+	}
+
+	// Execute ignore
+	fmt.Print(codeRunner.IgnoreBreakPoint(id, count))
+	return true
+}
+
+// regMatchEnable regex matching and executing enable and disable commands.
+func regMatchEnable(command string, codeRunner *coderunner.CodeRunner) bool {
+	// Match regex
+	var matches []string = REG_ENABLE.FindStringSubmatch(command)
+	if matches == nil {
+		return false
+	}
+
+	// Read arguments
+	var id int
+	fmt.Sscanf(matches[2], "%d", &id)
+
+	// Execute enable or disable
+	fmt.Print(codeRunner.EnableBreakPoint(id, matches[1] == "enable"))
+	return true
+}
+
 // regMatchDelete regex matching and executing delete command.
 func regMatchDelete(command string, codeRunner *coderunner.CodeRunner) bool {
 	// Match regex
@@ -426,15 +580,16 @@ func regMatchPeek(command string, codeRunner *coderunner.CodeRunner) bool {
 // printDebugMessage prints debug messages according to the return code.
 //
 // Used in run and continue commands.
-func printDebugMessage(ret coderunner.ReturnCode, codeRunning *bool) {
+func printDebugMessage(ret coderunner.ReturnCode, codeRunner *coderunner.CodeRunner, codeRunning *bool) {
 	switch ret {
 	case coderunner.ReturnReachBreakPoint:
-		fmt.Print("\n\nHit breakpoint\n\n")
+		fmt.Printf("\n\nHit breakpoint\n%v\n", codeRunner.BreakPointReport())
 		*codeRunning = true
 
 	case coderunner.ReturnReachWatch:
-		fmt.Print("\n\nWatch hit\n\n")
-		*codeRunning = true
+		// Watch may fire on the last operator
+		fmt.Printf("\n\nWatch hit\n%v\n", codeRunner.WatchReport())
+		*codeRunning = !codeRunner.Finished()
 
 	case coderunner.ReturnReachUntil:
 		fmt.Print("\n\nUntil finished\n\n")
@@ -448,16 +603,46 @@ func printDebugMessage(ret coderunner.ReturnCode, codeRunning *bool) {
 		fmt.Print("\n\nRunning finished\n\n")
 		*codeRunning = false
 
+	case coderunner.ReturnRuntimeError:
+		fmt.Printf("\n\n%v\n\n", codeRunner.RuntimeError().Error())
+		*codeRunning = false
+
 	default:
 		panic("DebugShell: Unknown return code")
 	}
 }
 
-// peekTape peeks memory bytes at the given offset and length, and prints them.
+// printReverseMessage prints messages of reverse commands according to the return code.
+//
+// Code is running at any position reached backwards, so continue can be used after it.
+func printReverseMessage(ret coderunner.ReturnCode, codeRunner *coderunner.CodeRunner, codeRunning *bool) {
+	switch ret {
+	case coderunner.ReturnReachBreakPoint:
+		fmt.Printf("Hit breakpoint\n%v\n", codeRunner.BreakPointReport())
+
+	case coderunner.ReturnReachWatch:
+		fmt.Printf("Watch hit\n%v\n", codeRunner.WatchReport())
+
+	case coderunner.ReturnReachUntil:
+		fmt.Print("Reverse until finished\n\n")
+
+	case coderunner.ReturnReachHistoryStart:
+		fmt.Print("Reached the beginning of history\n\n")
+
+	case coderunner.ReturnAfterStep:
+		// Nothing to show like step
+
+	default:
+		panic("DebugShell: Invalid return code")
+	}
+	*codeRunning = !codeRunner.Finished()
+}
+
+// peekTape peeks memory cells at the given offset and length, and prints them.
 func peekTape(codeRunner *coderunner.CodeRunner, offset, length int) {
-	var bytes []byte = codeRunner.PeekBytes(offset, length)
-	// Print bytes
-	for index, each := range bytes {
+	var cells []uint32 = codeRunner.PeekCells(offset, length)
+	// Print cells
+	for index, each := range cells {
 		if offset+index == 0 {
 			fmt.Printf("[%d] ", each)
 		} else {
@@ -477,8 +662,8 @@ func step(codeRunner *coderunner.CodeRunner, codeRunning *bool) (ret coderunner.
 	// Step show message briefly so don't use checkReturnCode function
 	switch ret {
 	case coderunner.ReturnReachWatch:
-		fmt.Print("Watch hit\n\n")
-		*codeRunning = true
+		fmt.Printf("Watch hit\n%v\n", codeRunner.WatchReport())
+		*codeRunning = !codeRunner.Finished()
 
 	case coderunner.ReturnReachUntil:
 		fmt.Print("Until finished\n\n")
@@ -495,6 +680,10 @@ func step(codeRunner *coderunner.CodeRunner, codeRunning *bool) (ret coderunner.
 	case coderunner.ReturnAfterStep:
 		*codeRunning = true
 
+	case coderunner.ReturnRuntimeError:
+		fmt.Printf("\n\n%v\n\n", codeRunner.RuntimeError().Error())
+		*codeRunning = false
+
 	default:
 		panic("DebugShell: Invalid return code")
 	}
@@ -524,6 +713,12 @@ func detailedStep(codeRunner *coderunner.CodeRunner, codeRunning *bool) (ret cod
 	if ret == coderunner.ReturnAfterFinish {
 		fmt.Print("\n\nRunning finished\n\n")
 		*codeRunning = false
+	} else if ret == coderunner.ReturnRuntimeError {
+		fmt.Printf("\n\n%v\n\n", codeRunner.RuntimeError().Error())
+		*codeRunning = false
+	} else if ret == coderunner.ReturnReachWatch {
+		fmt.Printf("Watch hit\n%v\n", codeRunner.WatchReport())
+		*codeRunning = !codeRunner.Finished()
 	} else {
 		*codeRunning = true
 	}
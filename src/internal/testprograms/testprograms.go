@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package testprograms loads the Brainfuck programs in testdata shared by tests of several packages.
+package testprograms
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Program is a test program, a .bf file with an optional .in input and the .out output expected with default options.
+type Program struct {
+	Name   string // File name without extension
+	Source string
+	Input  string
+	Output string
+}
+
+// Load returns every program in testdata/programs, which are quick enough to run at any level and engine.
+func Load(t testing.TB) (ret []Program) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(testdata(), "programs", "*.bf"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no test programs found: %v", err)
+	}
+	for _, path := range paths {
+		ret = append(ret, read(t, path))
+	}
+	return
+}
+
+// Find returns the named program in testdata/programs.
+func Find(t testing.TB, name string) Program {
+	t.Helper()
+	return read(t, filepath.Join(testdata(), "programs", name+".bf"))
+}
+
+// Bench returns the named program in testdata/bench, which runs long enough to measure engines.
+func Bench(t testing.TB, name string) Program {
+	t.Helper()
+	return read(t, filepath.Join(testdata(), "bench", name+".bf"))
+}
+
+// testdata returns the testdata directory of the module, found from this source file
+// so that tests of any package can use it.
+func testdata() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		panic("testprograms: can't find source file")
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata")
+}
+
+// read reads a program and its .in and .out files.
+func read(t testing.TB, path string) (ret Program) {
+	t.Helper()
+	var base string = strings.TrimSuffix(path, ".bf")
+	source, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, _ := os.ReadFile(base + ".in")
+	output, err := os.ReadFile(base + ".out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Program{Name: filepath.Base(base), Source: string(source), Input: string(input), Output: string(output)}
+}
@@ -18,31 +18,74 @@
 package main
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/Anslen/Bfck/codeManager/bytecode"
+	"github.com/Anslen/Bfck/codeManager/code"
+	codeanalyser "github.com/Anslen/Bfck/codeManager/codeAnalyser"
+	codecompiler "github.com/Anslen/Bfck/codeManager/codeCompiler"
 	codereader "github.com/Anslen/Bfck/codeManager/codeReader"
+	coderunner "github.com/Anslen/Bfck/codeManager/codeRunner"
+	"github.com/Anslen/Bfck/codeManager/jit"
 	debugshell "github.com/Anslen/Bfck/debugShell"
+	"github.com/Anslen/Bfck/memory"
 )
 
 // For IDE debug
 const MAIN_DEBUG = false
 const MAIN_DEBUG_FILE_PATH = ""
 
-const HELP_STRING string = "run <file_path>   : Run specified code file without debug\n" +
-	"debug <file_path> : Open debug shell with specified code file\n" +
-	"help              : Show this help message\n"
+const HELP_STRING string = "run [options] <file_path>     : Run specified code file without debug\n" +
+	"debug [options] <file_path>   : Open debug shell with specified code file\n" +
+	"compile [options] <file_path> : Compile specified code file to another language\n" +
+	"build [options] <file_path>   : Analyse specified code file and save it as bytecode\n" +
+	"exec [options] <file_path>    : Run specified bytecode file built by build\n" +
+	"help                          : Show this help message\n" +
+	"\nOptions:\n" +
+	"--eof=0|unchanged|-1          : Value stored by ',' at end of input, default 0\n" +
+	"--cell=8|16|32                : Bits of each memory cell, default 8\n" +
+	"--overflow=wrap|saturate|trap : Behaviour when a cell goes beyond its width, default wrap\n" +
+	"--tape=<size>                 : Fixed tape of addresses 0 to size-1, e.g. 30000, default unbounded\n" +
+	"--no-negative                 : Forbid moving left of the start cell\n" +
+	"--max-cells=<count>           : Maximum number of cells the program may use, default unlimited\n" +
+	"-O0|-O1|-O2                   : Optimisation level: no merging, merge +-<> only, or all, default -O2\n" +
+	"\nRun options:\n" +
+	"--engine=interp|threaded|jit  : Interpreter, handlers compiled in advance, or x86-64 machine code on\n" +
+	"                                linux/amd64, default interp\n" +
+	"                                JIT supports wrapping cells on an unbounded tape, otherwise interpreter is used\n" +
+	"\nBuild options (with -O above):\n" +
+	"--debug                       : Keep line begins so the bytecode can be debugged by exec --debug\n" +
+	"--output=<path>               : Output file, '-' for stdout, default source path with .bfc extension\n" +
+	"\nExec options (with run options above, -O is fixed by build):\n" +
+	"--debug                       : Open debug shell with bytecode built with --debug\n" +
+	"\nCompile options (with --eof, --cell, --tape and -O above):\n" +
+	"--target=<target>             : c, go, wasm, asm (x86-64 for GNU as), nasm or llvm (LLVM IR), default c\n" +
+	"--package=<name>              : Package name of Go output, default derived from file name\n" +
+	"--output=<path>               : Output file, '-' for stdout, default source path with target extension\n" +
+	"                                Compiled code uses wrapping cells on a tape of 30000 cells by default\n"
 
 const VERSION_STRING string = "Bfck version 0.0.1 - Copyright (C) 2026 Anslen"
 
 func main() {
+	// Shared by code runner and debug shell
+	var stdin *bufio.Reader = bufio.NewReader(os.Stdin)
+	var options coderunner.Options = coderunner.Options{Input: stdin, Output: os.Stdout}
+
 	if MAIN_DEBUG {
-		codeRunner, err := codereader.Read(MAIN_DEBUG_FILE_PATH, true)
+		codeRunner, err := codereader.Read(MAIN_DEBUG_FILE_PATH, true, codeanalyser.O2, options)
 		if err != nil {
 			fmt.Println(err.Error())
 			return
 		}
-		debugshell.Start(codeRunner)
+		debugshell.Start(codeRunner, stdin)
 		return
 	}
 
@@ -51,31 +94,285 @@ func main() {
 		return
 	}
 
-	if len(os.Args) != 3 {
+	if len(os.Args) < 3 {
 		fmt.Println("Unknown command. type 'help' for help.")
 		return
 	}
 
 	switch os.Args[1] {
 	case "run":
-		codeRunner, err := codereader.Read(os.Args[2], false)
+		var engineName string
+		path, level, err := parseArguments(os.Args[1], os.Args[2:], &options, func(flags *flag.FlagSet) {
+			flags.StringVar(&engineName, "engine", "interp", "")
+		})
 		if err != nil {
 			fmt.Println(err.Error())
 			return
 		}
-		codeRunner.Run()
-		fmt.Print("\n")
+
+		code, err := codereader.ReadCode(path, false, level)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		runCode(code, options, engineName)
 
 	case "debug":
-		codeRunner, err := codereader.Read(os.Args[2], true)
+		path, level, err := parseArguments(os.Args[1], os.Args[2:], &options, nil)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		codeRunner, err := codereader.Read(path, true, level, options)
 		if err != nil {
 			fmt.Println(err.Error())
 			return
 		}
 
-		debugshell.Start(codeRunner)
+		debugshell.Start(codeRunner, stdin)
+
+	case "compile":
+		var target, outputPath, packageName string
+		path, level, err := parseArguments(os.Args[1], os.Args[2:], &options, func(flags *flag.FlagSet) {
+			flags.StringVar(&target, "target", "c", "")
+			flags.StringVar(&outputPath, "output", "", "")
+			flags.StringVar(&packageName, "package", "", "")
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		err = compileFile(path, level, options, target, outputPath, packageName)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+	case "build":
+		var outputPath string
+		var debugFlag bool
+		path, level, err := parseArguments(os.Args[1], os.Args[2:], &options, func(flags *flag.FlagSet) {
+			flags.StringVar(&outputPath, "output", "", "")
+			flags.BoolVar(&debugFlag, "debug", false, "")
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		err = buildFile(path, debugFlag, level, outputPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+	case "exec":
+		var engineName string
+		var debugFlag bool
+		path, _, err := parseArguments(os.Args[1], os.Args[2:], &options, func(flags *flag.FlagSet) {
+			flags.StringVar(&engineName, "engine", "interp", "")
+			flags.BoolVar(&debugFlag, "debug", false, "")
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		file, err := codereader.ReadBytecode(path)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		warnChangedSource(path, file)
+
+		if !debugFlag {
+			runCode(file.Code, options, engineName)
+			return
+		}
+		if file.Code.LineBegins == nil {
+			fmt.Println("Error: bytecode was built without --debug")
+			return
+		}
+		debugshell.Start(coderunner.New(file.Code, true, options), stdin)
 
 	default:
 		fmt.Println("Unknown command. type 'help' for help.")
 	}
 }
+
+// runCode runs code without debug on the named engine, exiting with status 1 if the engine can't be created
+// or on runtime error.
+func runCode(c *code.Code, options coderunner.Options, engineName string) {
+	engine, err := newEngine(c, options, engineName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	var ret coderunner.ReturnCode = engine.Run()
+	fmt.Print("\n")
+
+	// Report runtime error with non-zero exit code
+	if ret == coderunner.ReturnRuntimeError {
+		fmt.Fprintln(os.Stderr, engine.RuntimeError().Error())
+		os.Exit(1)
+	}
+}
+
+// parseArguments parses options and the file path following a command, options are written into the given Options.
+//
+// The last of -O0, -O1 and -O2 wins, default is -O2. Command specific flags can be defined by extraFlags if not nil.
+func parseArguments(command string, args []string, options *coderunner.Options, extraFlags func(flags *flag.FlagSet)) (path string, level codeanalyser.OptLevel, err error) {
+	var flags *flag.FlagSet = flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard) // Errors are returned and printed by caller
+
+	var eof *string = flags.String("eof", "0", "")
+	var cellWidth *int = flags.Int("cell", memory.CellWidth8, "")
+	var overflow *string = flags.String("overflow", "wrap", "")
+	var tapeSize *int = flags.Int("tape", 0, "")
+	var noNegative *bool = flags.Bool("no-negative", false, "")
+	var maxCells *int = flags.Int("max-cells", 0, "")
+	level = codeanalyser.O2
+	for _, name := range []string{"O0", "O1", "O2"} {
+		flags.BoolFunc(name, "", func(string) (err error) {
+			level, err = codeanalyser.ParseOptLevel(name)
+			return
+		})
+	}
+	if extraFlags != nil {
+		extraFlags(flags)
+	}
+
+	// Parse options, file path should be the only argument left
+	err = flags.Parse(args)
+	if err != nil {
+		err = fmt.Errorf("Error: %v", err)
+		return
+	}
+	if flags.NArg() != 1 {
+		err = errors.New("Error: expect exactly one file path after options")
+		return
+	}
+	path = flags.Arg(0)
+
+	// Convert options
+	options.EOF, err = coderunner.ParseEOFPolicy(*eof)
+	if err != nil {
+		return
+	}
+	if !memory.IsValidCellWidth(*cellWidth) {
+		err = fmt.Errorf("Error: unsupported cell width %v, expect 8, 16 or 32", *cellWidth)
+		return
+	}
+	if *tapeSize < 0 || *maxCells < 0 {
+		err = errors.New("Error: tape size and maximum cell count can't be negative")
+		return
+	}
+	options.Memory.CellWidth = *cellWidth
+	options.Memory.TapeSize = *tapeSize
+	options.Memory.NoNegative = *noNegative
+	options.Memory.MaxCells = *maxCells
+	options.Memory.Overflow, err = memory.ParseOverflowPolicy(*overflow)
+	return
+}
+
+// newEngine prepares the named engine to run code without debug.
+//
+// JIT falls back to the interpreter with a note on stderr if it can't run on this platform or with these options.
+func newEngine(c *code.Code, options coderunner.Options, engineName string) (ret coderunner.Engine, err error) {
+	switch engineName {
+	case "interp":
+		return coderunner.New(c, false, options), nil
+
+	case "threaded":
+		return coderunner.NewThreaded(c, options), nil
+
+	case "jit":
+		runner, err := jit.New(c, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Note: %v, using interpreter\n", err)
+			return coderunner.New(c, false, options), nil
+		}
+		return runner, nil
+	}
+	return nil, fmt.Errorf("Error: unknown engine %q, expect interp, threaded or jit", engineName)
+}
+
+// buildFile analyses the code file and writes it as bytecode, next to the source file if outputPath is empty.
+func buildFile(path string, debugFlag bool, level codeanalyser.OptLevel, outputPath string) (err error) {
+	file, err := codereader.Build(path, debugFlag, level)
+	if err != nil {
+		return
+	}
+
+	// Write to stdout or file
+	if outputPath == "-" {
+		return bytecode.Write(os.Stdout, file)
+	}
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(path, filepath.Ext(path)) + bytecode.Extension
+	}
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return
+	}
+	err = bytecode.Write(output, file)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
+// warnChangedSource warns on stderr if the source file next to the bytecode file, with the same name
+// and .bf extension, is no longer the source it was built from.
+func warnChangedSource(path string, file *bytecode.File) {
+	var sourcePath string = strings.TrimSuffix(path, filepath.Ext(path)) + ".bf"
+	source, err := os.ReadFile(sourcePath)
+	if err == nil && sha256.Sum256(source) != file.SourceHash {
+		fmt.Fprintf(os.Stderr, "Warning: %v has changed since the bytecode was built\n", sourcePath)
+	}
+}
+
+// compileFile compiles the code file for the target, output is written next to the source file if outputPath is empty.
+func compileFile(path string, level codeanalyser.OptLevel, options coderunner.Options, targetName string, outputPath string, packageName string) (err error) {
+	target, err := codecompiler.ParseTarget(targetName)
+	if err != nil {
+		return
+	}
+	if options.Memory.Overflow != memory.OverflowWrap || options.Memory.MaxCells != 0 {
+		return errors.New("Error: compiled code only supports wrapping cells, use --tape to limit memory")
+	}
+	if options.Memory.NoNegative {
+		return errors.New("Error: compiled code always stops left of address 0, --no-negative is not supported")
+	}
+
+	code, err := codereader.ReadCode(path, false, level)
+	if err != nil {
+		return
+	}
+	var compileOptions codecompiler.Options = codecompiler.Options{
+		Source:    filepath.Base(path),
+		TapeSize:  options.Memory.TapeSize,
+		CellWidth: options.Memory.CellWidth,
+		EOF:       options.EOF,
+		Package:   packageName,
+	}
+
+	// Write to stdout or file
+	if outputPath == "-" {
+		return codecompiler.Compile(code, target, compileOptions, os.Stdout)
+	}
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(path, filepath.Ext(path)) + target.Extension()
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return
+	}
+	err = codecompiler.Compile(code, target, compileOptions, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
@@ -0,0 +1,264 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import "bytes"
+
+// initialTapeSize is the number of cells allocated for a new tape, start cell is in the middle.
+const initialTapeSize = 1024
+
+type cell interface {
+	~uint8 | ~uint16 | ~uint32
+}
+
+// flatTape stores all cells in a single slice with a movable origin, growing in both directions.
+type flatTape[T cell] struct {
+	cells   []T
+	origin  int // Index of address 0 in cells
+	index   int // Index of current cell in cells
+	lowest  int // Lowest address ever reached, only tracked when limited
+	highest int // Highest address ever reached, only tracked when limited
+	limited bool
+	free    uint // Indices below free are reached by MovePtr without checks, 0 when limited
+	config  Config
+}
+
+func newFlatTape[T cell](config Config) (ret *flatTape[T]) {
+	ret = &flatTape[T]{
+		cells:   make([]T, initialTapeSize),
+		origin:  initialTapeSize / 2,
+		index:   initialTapeSize / 2,
+		limited: config.TapeSize > 0 || config.NoNegative || config.MaxCells > 0,
+		config:  config,
+	}
+	if !ret.limited {
+		ret.free = initialTapeSize
+	}
+	return
+}
+
+// Pointer returns the address of the current cell.
+func (t *flatTape[T]) Pointer() int {
+	return t.index - t.origin
+}
+
+// Peek returns the cell at the current pointer plus the given offset, 0 for unused cells.
+func (t *flatTape[T]) Peek(offset int) uint32 {
+	// One unsigned compare covers both ends
+	var index int = t.index + offset
+	if uint(index) >= uint(len(t.cells)) {
+		return 0
+	}
+	return uint32(t.cells[index])
+}
+
+// PeekCells returns cells starting from the current pointer plus the given offset.
+func (t *flatTape[T]) PeekCells(offset, length int) (ret []uint32) {
+	ret = make([]uint32, length)
+
+	// Copy the part inside allocated cells, others stay 0
+	var begin int = t.index + offset
+	for i := max(0, -begin); i < length && begin+i < len(t.cells); i++ {
+		ret[i] = uint32(t.cells[begin+i])
+	}
+	return
+}
+
+// Poke sets the current cell to the given value.
+func (t *flatTape[T]) Poke(value uint32) {
+	t.cells[t.index] = T(value)
+}
+
+// PokeAt sets the cell at the current pointer plus offset to the given value.
+func (t *flatTape[T]) PokeAt(offset int, value uint32) {
+	t.cells[t.index+offset] = T(value)
+}
+
+// Add adds the given value to the current cell, following the overflow policy.
+func (t *flatTape[T]) Add(value uint64) error {
+	return t.AddAt(0, value)
+}
+
+// Sub subtracts the given value from the current cell, following the overflow policy.
+func (t *flatTape[T]) Sub(value uint64) error {
+	return t.SubAt(0, value)
+}
+
+// AddAt adds the given value to the cell at the current pointer plus offset, following the overflow policy.
+func (t *flatTape[T]) AddAt(offset int, value uint64) error {
+	var index int = t.index + offset
+	var current T = t.cells[index]
+
+	// Check overflow, compare with remaining space to avoid overflow of uint64 itself
+	if t.config.Overflow != OverflowWrap && value > uint64(^T(0)-current) {
+		if t.config.Overflow == OverflowTrap {
+			return ErrOverflow
+		}
+		t.cells[index] = ^T(0)
+		return nil
+	}
+
+	// Conversion truncates value to cell width, which is the same as wrapping
+	t.cells[index] = current + T(value)
+	return nil
+}
+
+// SubAt subtracts the given value from the cell at the current pointer plus offset, following the overflow policy.
+func (t *flatTape[T]) SubAt(offset int, value uint64) error {
+	var index int = t.index + offset
+	var current T = t.cells[index]
+
+	// Check underflow
+	if t.config.Overflow != OverflowWrap && value > uint64(current) {
+		if t.config.Overflow == OverflowTrap {
+			return ErrUnderflow
+		}
+		t.cells[index] = 0
+		return nil
+	}
+
+	t.cells[index] = current - T(value)
+	return nil
+}
+
+// MovePtr moves the pointer by the given offset, growing cells if needed.
+//
+// Kept small enough to inline, moves checking limits or growing cells go to moveFar.
+func (t *flatTape[T]) MovePtr(offset int) (err error) {
+	// Most moves stay inside allocated cells of an unlimited tape
+	if uint(t.index+offset) < t.free {
+		t.index += offset
+		return nil
+	}
+	return t.moveFar(offset)
+}
+
+// moveFar moves the pointer by the given offset, checking tape limits and growing cells.
+//
+// Not inlined, or MovePtr would grow too large to inline itself.
+//
+//go:noinline
+func (t *flatTape[T]) moveFar(offset int) (err error) {
+	err = t.Extend(offset, offset)
+	if err != nil {
+		return
+	}
+	t.index += offset
+	return nil
+}
+
+// Scan moves the pointer by stride until the current cell is zero, staying if it already is.
+func (t *flatTape[T]) Scan(stride int) (err error) {
+	var offset int = t.findZero(stride) - t.index
+	if offset == 0 {
+		return nil
+	}
+
+	// Make the whole way usable at once
+	err = t.Extend(min(offset, 0), max(offset, 0))
+	if err != nil {
+		// A limit breaks on the way, move stride by stride to stop at the same place as the loop
+		for t.cells[t.index] != 0 {
+			err = t.MovePtr(stride)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	}
+
+	t.index += offset
+	return nil
+}
+
+// findZero returns the index of the first zero cell from current index by stride.
+//
+// Cells outside allocated range are zero, so the result may be out of range.
+func (t *flatTape[T]) findZero(stride int) (ret int) {
+	// Search contiguous cells with byte search for the common [>] and [<]
+	if cells, ok := any(t.cells).([]uint8); ok && (stride == 1 || stride == -1) {
+		if stride == 1 {
+			ret = bytes.IndexByte(cells[t.index:], 0)
+			if ret < 0 {
+				return len(t.cells)
+			}
+			return t.index + ret
+		}
+		return bytes.LastIndexByte(cells[:t.index+1], 0)
+	}
+
+	ret = t.index
+	for ret >= 0 && ret < len(t.cells) && t.cells[ret] != 0 {
+		ret += stride
+	}
+	return
+}
+
+// Extend makes cells from the current pointer plus low to plus high usable without moving the pointer.
+func (t *flatTape[T]) Extend(low, high int) (err error) {
+	// Check tape limits as if the pointer reached there
+	if t.limited {
+		var address int = t.index - t.origin
+		err = checkLimits(&t.config, address+low, address+high, t.lowest, t.highest)
+		if err != nil {
+			return
+		}
+		t.lowest = min(t.lowest, address+low)
+		t.highest = max(t.highest, address+high)
+	}
+
+	// Grow each side if needed, growing left moves index
+	if t.index+low < 0 {
+		t.grow(t.index + low)
+	}
+	if t.index+high >= len(t.cells) {
+		t.grow(t.index + high)
+	}
+	return nil
+}
+
+// Clone returns an independent copy of the tape, including the pointer and addresses reached before.
+func (t *flatTape[T]) Clone() Tape {
+	var ret flatTape[T] = *t
+	ret.cells = make([]T, len(t.cells))
+	copy(ret.cells, t.cells)
+	return &ret
+}
+
+// grow enlarges cells so that the target index is inside, at least doubling the size towards the growing direction.
+//
+// Growing left moves existing cells right, so origin and index are adjusted.
+func (t *flatTape[T]) grow(target int) {
+	var size int = len(t.cells)
+
+	if target < 0 {
+		var extra int = max(size, -target)
+		var cells []T = make([]T, size+extra)
+		copy(cells[extra:], t.cells)
+		t.cells = cells
+		t.origin += extra
+		t.index += extra
+	} else {
+		var extra int = max(size, target-size+1)
+		t.cells = append(t.cells, make([]T, extra)...)
+	}
+
+	if !t.limited {
+		t.free = uint(len(t.cells))
+	}
+}
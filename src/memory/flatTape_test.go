@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2026 Anslen
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import "testing"
+
+// blockTape is the tape used before flatTape: a doubly linked list of byte blocks with the pointer
+// kept in the current block, kept here to compare speed with. Benchmarks use both through their
+// concrete types with 8-bit cells.
+type blockTape struct {
+	cells []byte
+	ptr   int
+	prev  *blockTape
+	next  *blockTape
+}
+
+const blockSize = 1024
+
+func newBlockTape() (ret *blockTape) {
+	ret = &blockTape{
+		cells: make([]byte, blockSize),
+		ptr:   blockSize / 2,
+	}
+	return
+}
+
+func (m *blockTape) Peek(offset int) (ret byte) {
+	var index int = m.ptr + offset
+	var current *blockTape = m
+
+	for index < 0 {
+		if current.prev == nil {
+			return 0
+		}
+		index += blockSize
+		current = current.prev
+	}
+
+	for index >= blockSize {
+		if current.next == nil {
+			return 0
+		}
+		index -= blockSize
+		current = current.next
+	}
+
+	return current.cells[index]
+}
+
+func (m *blockTape) PeekBytes(offset, length int) (ret []byte) {
+	ret = make([]byte, length)
+	for i := 0; i < length; i++ {
+		ret[i] = m.Peek(offset + i)
+	}
+	return
+}
+
+func (m *blockTape) Add(value uint64) {
+	m.cells[m.ptr] += byte(value)
+}
+
+func (m *blockTape) MovePtr(offset int) (ret *blockTape) {
+	ret = m
+	ret.ptr += offset
+
+	for ret.ptr < 0 {
+		if ret.prev == nil {
+			ret.prev = newBlockTape()
+			ret.prev.next = ret
+		}
+		ret.prev.ptr = ret.ptr + blockSize
+		ret = ret.prev
+	}
+	for ret.ptr >= blockSize {
+		if ret.next == nil {
+			ret.next = newBlockTape()
+			ret.next.prev = ret
+		}
+		ret.next.ptr = ret.ptr - blockSize
+		ret = ret.next
+	}
+	return
+}
+
+// sweepCells is the number of cells walked by BenchmarkSweep, like the classic tape.
+const sweepCells = 30000
+
+// BenchmarkSweep adds to every cell of a classic sized tape walking right, then walks back.
+func BenchmarkSweep(b *testing.B) {
+	b.Run("flat", func(b *testing.B) {
+		var tape *flatTape[uint8] = newFlatTape[uint8](Config{})
+		for b.Loop() {
+			for range sweepCells {
+				tape.Add(1)
+				tape.MovePtr(1)
+			}
+			tape.MovePtr(-sweepCells)
+		}
+	})
+	b.Run("block", func(b *testing.B) {
+		var tape *blockTape = newBlockTape()
+		for b.Loop() {
+			for range sweepCells {
+				tape.Add(1)
+				tape = tape.MovePtr(1)
+			}
+			tape = tape.MovePtr(-sweepCells)
+		}
+	})
+}
+
+// BenchmarkPeekNearby reads cells around the pointer like offset addressing does, from every cell of a block.
+func BenchmarkPeekNearby(b *testing.B) {
+	b.Run("flat", func(b *testing.B) {
+		var tape *flatTape[uint8] = newFlatTape[uint8](Config{})
+		tape.Extend(-blockSize, 2*blockSize)
+		var sum uint32
+		for b.Loop() {
+			for range blockSize {
+				for offset := -8; offset <= 8; offset++ {
+					sum += tape.Peek(offset)
+				}
+				tape.MovePtr(1)
+			}
+			tape.MovePtr(-blockSize)
+		}
+		_ = sum
+	})
+	b.Run("block", func(b *testing.B) {
+		var tape *blockTape = newBlockTape()
+		tape = tape.MovePtr(-blockSize).MovePtr(3 * blockSize).MovePtr(-2 * blockSize)
+		var sum byte
+		for b.Loop() {
+			for range blockSize {
+				for offset := -8; offset <= 8; offset++ {
+					sum += tape.Peek(offset)
+				}
+				tape = tape.MovePtr(1)
+			}
+			tape = tape.MovePtr(-blockSize)
+		}
+		_ = sum
+	})
+}
+
+// BenchmarkPeekWindow reads a window of cells spanning several blocks, like the debugger showing memory.
+func BenchmarkPeekWindow(b *testing.B) {
+	b.Run("flat", func(b *testing.B) {
+		var tape *flatTape[uint8] = newFlatTape[uint8](Config{})
+		tape.Extend(-4*blockSize, 4*blockSize)
+		for b.Loop() {
+			tape.PeekCells(-4*blockSize, 8*blockSize)
+		}
+	})
+	b.Run("block", func(b *testing.B) {
+		var tape *blockTape = newBlockTape()
+		tape = tape.MovePtr(-4 * blockSize).MovePtr(8 * blockSize).MovePtr(-4 * blockSize)
+		for b.Loop() {
+			tape.PeekBytes(-4*blockSize, 8*blockSize)
+		}
+	})
+}
+
+// BenchmarkGrow walks right on a new tape until it has grown to 1<<16 cells.
+func BenchmarkGrow(b *testing.B) {
+	b.Run("flat", func(b *testing.B) {
+		for b.Loop() {
+			var tape *flatTape[uint8] = newFlatTape[uint8](Config{})
+			for range 1 << 16 {
+				tape.MovePtr(1)
+				tape.Add(1)
+			}
+		}
+	})
+	b.Run("block", func(b *testing.B) {
+		for b.Loop() {
+			var tape *blockTape = newBlockTape()
+			for range 1 << 16 {
+				tape = tape.MovePtr(1)
+				tape.Add(1)
+			}
+		}
+	})
+}
@@ -17,95 +17,180 @@
 
 package memory
 
-const MemoryBlockSize = 1024
+import (
+	"errors"
+	"fmt"
+)
 
-type Memory struct {
-	cells []byte
-	ptr   int
-	prev  *Memory
-	next  *Memory
+// Supported cell widths in bits.
+const (
+	CellWidth8  = 8
+	CellWidth16 = 16
+	CellWidth32 = 32
+)
+
+type OverflowPolicy byte
+
+// Overflow policies decide what happens when a cell goes beyond its width.
+const (
+	OverflowWrap     = iota // Wrap around, the classic behaviour
+	OverflowSaturate        // Stay at 0 or maximum value
+	OverflowTrap            // Leave cell unchanged and report ErrOverflow or ErrUnderflow
+)
+
+// ClassicTapeSize is the tape size of the original Brainfuck implementation.
+const ClassicTapeSize = 30000
+
+var (
+	ErrOverflow     = errors.New("cell overflow")
+	ErrUnderflow    = errors.New("cell underflow")
+	ErrLeftEdge     = errors.New("pointer moved left of address 0")
+	ErrTapeEnd      = errors.New("pointer moved beyond tape end")
+	ErrTooManyCells = errors.New("too many cells used")
+)
+
+// Config configures memory, zero value of each field means default.
+//
+// Addresses are relative to the start cell, which is address 0.
+type Config struct {
+	CellWidth  int            // Bits of each cell, 8, 16 or 32, default 8
+	Overflow   OverflowPolicy // Default OverflowWrap
+	TapeSize   int            // Fixed tape of addresses [0, TapeSize), implies NoNegative, 0 means unbounded
+	NoNegative bool           // Forbid negative addresses
+	MaxCells   int            // Maximum distance between lowest and highest used address plus 1, 0 means unlimited
 }
 
-func New() (ret *Memory) {
-	ret = &Memory{
-		cells: make([]byte, MemoryBlockSize),
-		ptr:   MemoryBlockSize / 2,
-	}
-	return
+// Tape is the memory tape of Brainfuck, which grows in both directions as the pointer moves.
+//
+// Cell values are passed as uint32 and truncated to cell width.
+type Tape interface {
+	// Pointer returns the address of the current cell.
+	Pointer() int
+
+	// Peek returns the cell at the current pointer plus the given offset, 0 for unused cells.
+	Peek(offset int) uint32
+
+	// PeekCells returns cells starting from the current pointer plus the given offset.
+	PeekCells(offset, length int) []uint32
+
+	// Poke sets the current cell to the given value.
+	Poke(value uint32)
+
+	// PokeAt is Poke for the cell at the current pointer plus offset, which must be extended before.
+	PokeAt(offset int, value uint32)
+
+	// Add adds the given value to the current cell, following the overflow policy.
+	//
+	// Only returns ErrOverflow when overflow policy is OverflowTrap.
+	Add(value uint64) error
+
+	// Sub subtracts the given value from the current cell, following the overflow policy.
+	//
+	// Only returns ErrUnderflow when overflow policy is OverflowTrap.
+	Sub(value uint64) error
+
+	// AddAt is Add for the cell at the current pointer plus offset, which must be extended before.
+	AddAt(offset int, value uint64) error
+
+	// SubAt is Sub for the cell at the current pointer plus offset, which must be extended before.
+	SubAt(offset int, value uint64) error
+
+	// MovePtr moves the pointer by the given offset.
+	//
+	// If the move breaks tape limits, pointer stays and the error describes which limit is broken.
+	MovePtr(offset int) error
+
+	// Scan moves the pointer by stride until the current cell is zero, staying if it already is.
+	//
+	// If a move breaks tape limits, pointer stops before it and the error describes which limit is broken.
+	Scan(stride int) error
+
+	// Extend makes cells from the current pointer plus low to plus high usable without moving the pointer,
+	// checking tape limits as if the pointer had visited them. Nothing changes if any limit is broken.
+	Extend(low, high int) error
+
+	// Clone returns an independent copy of the tape, including the pointer and addresses reached before.
+	Clone() Tape
 }
 
-// Peek returns the byte at the current pointer plus the given offset.
-func (m *Memory) Peek(offset int) (ret byte) {
-	var index int = m.ptr + offset
-	var current *Memory = m
+// New creates an empty tape with the given config.
+func New(config Config) (ret Tape) {
+	switch config.CellWidth {
+	case 0, CellWidth8:
+		return newFlatTape[uint8](config)
 
-	for index < 0 {
-		if current.prev == nil {
-			return 0
-		}
-		index += MemoryBlockSize
-		current = current.prev
-	}
+	case CellWidth16:
+		return newFlatTape[uint16](config)
 
-	for index >= MemoryBlockSize {
-		if current.next == nil {
-			return 0
-		}
-		index -= MemoryBlockSize
-		current = current.next
+	case CellWidth32:
+		return newFlatTape[uint32](config)
 	}
-
-	return current.cells[index]
+	panic("Memory: unsupported cell width")
 }
 
-// PeekBytes returns a slice of bytes starting from the current pointer plus the given offset.
-func (m *Memory) PeekBytes(offset, length int) (ret []byte) {
-	ret = make([]byte, length)
-	for i := 0; i < length; i++ {
-		ret[i] = m.Peek(offset + i)
+// ParseOverflowPolicy converts text "wrap", "saturate" or "trap" to OverflowPolicy.
+func ParseOverflowPolicy(text string) (ret OverflowPolicy, err error) {
+	switch text {
+	case "wrap":
+		ret = OverflowWrap
+
+	case "saturate":
+		ret = OverflowSaturate
+
+	case "trap":
+		ret = OverflowTrap
+
+	default:
+		err = fmt.Errorf("Error: unknown overflow policy %q, expect wrap, saturate or trap", text)
 	}
 	return
 }
 
-// Poke sets the byte at the current pointer to the given value.
-func (m *Memory) Poke(value byte) {
-	m.cells[m.ptr] = value
-}
+// CellMax returns the maximum value of a cell with the given width.
+func CellMax(cellWidth int) uint64 {
+	switch cellWidth {
+	case 0, CellWidth8:
+		return 0xFF
+
+	case CellWidth16:
+		return 0xFFFF
 
-// Add adds the given value to the byte at the current pointer.
-func (m *Memory) Add(value uint64) {
-	m.cells[m.ptr] += byte(value)
+	case CellWidth32:
+		return 0xFFFFFFFF
+	}
+	panic("Memory: unsupported cell width")
 }
 
-// Sub subtracts the given value from the byte at the current pointer.
-func (m *Memory) Sub(value uint64) {
-	m.cells[m.ptr] -= byte(value)
+// IsValidCellWidth reports whether the given cell width is supported.
+func IsValidCellWidth(cellWidth int) bool {
+	switch cellWidth {
+	case 0, CellWidth8, CellWidth16, CellWidth32:
+		return true
+	}
+	return false
 }
 
-// MovePtr moves the pointer by the given offset, returning the Memory block where the pointer ends up.
+// checkLimits checks whether the pointer can visit addresses from low to high.
 //
-// WARNING: Old Memory maybe invalid after calling this function.
-func (m *Memory) MovePtr(offset int) (ret *Memory) {
-	ret = m
-	ret.ptr += offset
-
-	// Check bounds and move to next/prev block if necessary
-	for ret.ptr < 0 {
-		if ret.prev == nil {
-			ret.prev = New()
-			ret.prev.next = ret
-		}
-		ret.prev.ptr = ret.ptr + MemoryBlockSize
-		ret = ret.prev
+// lowest and highest are the lowest and highest addresses reached before. The error reports the first address
+// beyond the limits, so it doesn't depend on how many steps a move takes.
+func checkLimits(config *Config, low, high, lowest, highest int) (err error) {
+	if (config.NoNegative || config.TapeSize > 0) && low < 0 {
+		return fmt.Errorf("%w (address %v)", ErrLeftEdge, -1)
 	}
-	for ret.ptr >= MemoryBlockSize {
-		if ret.next == nil {
-			ret.next = New()
-			ret.next.prev = ret
+
+	if config.TapeSize > 0 && high >= config.TapeSize {
+		return fmt.Errorf("%w (address %v, tape size %v)", ErrTapeEnd, config.TapeSize, config.TapeSize)
+	}
+
+	if config.MaxCells > 0 && max(highest, high)-min(lowest, low)+1 > config.MaxCells {
+		// Report the first address out of range
+		var address int = lowest + config.MaxCells
+		if low < lowest {
+			address = highest - config.MaxCells
 		}
-		ret.next.ptr = ret.ptr - MemoryBlockSize
-		ret = ret.next
+		return fmt.Errorf("%w (address %v needs %v cells, limit %v)", ErrTooManyCells, address, config.MaxCells+1, config.MaxCells)
 	}
 
-	return
+	return nil
 }
@@ -0,0 +1,2 @@
+Printable characters counted down from ninety five
+++++++++++[>+++++++++<-]>+++++>++++[<<++++++++>>-]<[<.+>-]
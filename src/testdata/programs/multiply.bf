@@ -0,0 +1,4 @@
+Nested loops run as multiply
++++++[>+++++[>+++<-]<-]>>.
++++[>++[>+++[>++++<-]<-]<-]>>>.
+>++++++[<-->-]<.
@@ -0,0 +1,2 @@
+Cells at offsets from the pointer
+>+++<[>>+<<-]>>>++++<<<+++[>+>++<<-]>.>.>.>.
@@ -0,0 +1,8 @@
+++++++++
+
+<<[<<<][[->>+++<<>----<][<<<<-------[->-<>>-<<][
+<<<<-----------+
+.][>>>.<<<<][
+
+.+++++++
+.<<<<.]]]>.[>>]<<[>>],[,[++[----<<<<<<<<[>]].>]------<<<++++++++++<<+[-]][->++++<>>>+<<<]
\ No newline at end of file
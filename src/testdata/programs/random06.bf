@@ -0,0 +1,5 @@
+[<<[[,,[-][-<<<+++>>>],][+]------------.][->>>+<<<>>>---<<<]
+[-------------------[-<<<-->>>>>++<<>++<]]
+].[-<<+++>>>>>---<<<]
+[>]+
+---------++.++++<<<<.
\ No newline at end of file
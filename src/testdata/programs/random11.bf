@@ -0,0 +1,13 @@
+>>>>>>>><[-<<<+>>><<++++>>]
+
+
+-----[[->>----<<>+<<<+++>>][-<<---->>>>++++<<]>>->>>>][<-------++++++++++++[[-<+>][+++++++++.[-<+><---->]
+[->>--<<]
+>>>>][[>]>--------
+[->+++<>>>++<<<]+++++++
+][-<<+>><<++++>>][<][>>>++[->>----<<>>++<<]]+++++++++.]<<<[>]-----------------][>]
+>>>,+++
+>>>>++++++++++++..[[<]+++++++++++++,[[+++.<<<<.].[
+>>]--,]<<[+][[[->++++<>----<]>>
+]
++++++++++]][[->>>++++<<<<<---->>][<<<]>>>>]
\ No newline at end of file
@@ -0,0 +1,11 @@
+.[-][>>]-------[<<<
+++++++++++++[-<->]]----------
+>[++++
+.[>]++++++++++],+++,[-<<<++++>>>],.>
+[>[<<<<>>><[-<---->]..[.<++++++++++++--------------
+--------
+]
+]+++++[>>],.<]<<--<<<<[>>][[-][[->+<<++++>][>]
+
+>][<
+[<]]<]
\ No newline at end of file
@@ -0,0 +1,3 @@
+>>>[->>>+<<<>>++<<]-------,
++++++++++++<<..----------[>>>><[<]
++++----<<]<<<<,++++++++++++++++
\ No newline at end of file
@@ -0,0 +1,4 @@
+<<<<<<<.+++++---->>>>++++++++>..+++++++,[->++<>-<]++++++++------
+++++++++++++<<
+>>>>[...,
+++++++++].---------
\ No newline at end of file
@@ -0,0 +1,6 @@
+.>
+[->>>---<<<>-<]>>>++++++>>>[->++++<],++++++++++[
+
+>>++,[-<<---->><+++>]][+]>>[->++<<<++++>><-->][+++++++<<
+>>><<<[->>++++<<>>+++<<<+++>]]++++++
+.[-<<<->>>>-<]
\ No newline at end of file
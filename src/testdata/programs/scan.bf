@@ -0,0 +1,4 @@
+Scans to right and left with strides of one and two
++>+>+>+>+<<<<[>]++++++++[<++++++++>-]<+.
+[<]>.
+>>>>>>>>+<<+<<+<<+<<[>>]<<.
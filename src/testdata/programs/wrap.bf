@@ -0,0 +1,3 @@
+Wrapping below zero and clearing by wrapping
+-.[+]+.
++++++[-]>+++[-<+++>]<.